@@ -25,7 +25,8 @@ import (
 
 // CheckpointBackupSpec defines the desired state of CheckpointBackup
 type CheckpointBackupSpec struct {
-	// Schedule specifies the backup schedule in cron format
+	// Schedule specifies the backup schedule in cron format.
+	// Standard five-field expressions and descriptors such as @hourly or @every 10m are supported.
 	// +required
 	Schedule string `json:"schedule"`
 
@@ -44,16 +45,144 @@ type CheckpointBackupSpec struct {
 	// Containers specifies the container configurations for checkpoints
 	// +optional
 	Containers []Container `json:"containers,omitempty"`
+
+	// StartingDeadlineSeconds is the deadline in seconds for starting a checkpoint
+	// that missed its scheduled time. Missed checkpoints older than the deadline are skipped.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// ConcurrencyPolicy specifies how to treat a scheduled checkpoint while the previous one is still running
+	// +optional
+	// +kubebuilder:default=Forbid
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Suspend tells the controller to suspend subsequent checkpoints.
+	// It does not apply to checkpoints that have already started.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// HistoryLimit is the number of successful checkpoints kept in the status history,
+	// which are the restore points available to a CheckpointRestore
+	// +optional
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+}
+
+// ContainerCheckpoint records the checkpoint archive created for a container
+type ContainerCheckpoint struct {
+	// Name of the container
+	// +required
+	Name string `json:"name"`
+
+	// ArchivePath is the path of the checkpoint archive on the node
+	// +optional
+	ArchivePath string `json:"archivePath,omitempty"`
+
+	// CheckpointTime is when the checkpoint archive was created
+	// +optional
+	CheckpointTime *metav1.Time `json:"checkpointTime,omitempty"`
+
+	// Image is the reference of the checkpoint image pushed to the registry
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Digest is the digest of the pushed checkpoint image manifest
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// Size is the size in bytes of the checkpoint archive
+	// +optional
+	Size int64 `json:"size,omitempty"`
 }
 
+// CheckpointRecord records the checkpoint images of one successful checkpoint of the pod
+type CheckpointRecord struct {
+	// Time is when the checkpoint was taken
+	// +required
+	Time metav1.Time `json:"time"`
+
+	// Containers records the checkpoint image of each container
+	// +optional
+	Containers []ContainerCheckpoint `json:"containers,omitempty"`
+}
+
+// CheckpointBackupPhase is a label for the condition of a CheckpointBackup at the current time
+// +kubebuilder:validation:Enum=Pending;Scheduled;Checkpointing;Uploading;Succeeded;Failed
+type CheckpointBackupPhase string
+
+const (
+	// CheckpointBackupPending means the referenced pod is not running yet, so no checkpoint can be taken
+	CheckpointBackupPending CheckpointBackupPhase = "Pending"
+	// CheckpointBackupScheduled means the backup is waiting for its next schedule time
+	CheckpointBackupScheduled CheckpointBackupPhase = "Scheduled"
+	// CheckpointBackupCheckpointing means the kubelet is checkpointing the containers of the pod
+	CheckpointBackupCheckpointing CheckpointBackupPhase = "Checkpointing"
+	// CheckpointBackupUploading means the checkpoint archives are being pushed to the registry
+	CheckpointBackupUploading CheckpointBackupPhase = "Uploading"
+	// CheckpointBackupSucceeded means the last checkpoint was taken and pushed successfully
+	CheckpointBackupSucceeded CheckpointBackupPhase = "Succeeded"
+	// CheckpointBackupFailed means the last checkpoint failed or the backup cannot be scheduled
+	CheckpointBackupFailed CheckpointBackupPhase = "Failed"
+)
+
+const (
+	// CheckpointBackupConditionScheduled indicates whether the schedule is valid and active
+	CheckpointBackupConditionScheduled = "Scheduled"
+	// CheckpointBackupConditionReady indicates whether the last checkpoint was taken and pushed successfully
+	CheckpointBackupConditionReady = "Ready"
+)
+
 // CheckpointBackupStatus defines the observed state of CheckpointBackup.
 type CheckpointBackupStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Phase is the current phase of the CheckpointBackup
+	// +optional
+	Phase CheckpointBackupPhase `json:"phase,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the CheckpointBackup's state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// NodeName is the node the checkpointed pod was running on
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// LastSuccessfulTime is when the last successful checkpoint of the pod was taken
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// Containers records the checkpoint images of the last successful checkpoint
+	// +optional
+	Containers []ContainerCheckpoint `json:"containers,omitempty"`
+
+	// History records the most recent successful checkpoints, newest first, up to Spec.HistoryLimit
+	// +optional
+	History []CheckpointRecord `json:"history,omitempty"`
+
+	// LastScheduleTime is the last time a checkpoint was scheduled
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// NextScheduleTime is the next time a checkpoint is scheduled
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Pod",type=string,JSONPath=`.spec.podRef.name`
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.metadata.labels.target-cluster`
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Last Success",type=date,JSONPath=`.status.lastSuccessfulTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // CheckpointBackup is the Schema for the checkpointbackups API
 type CheckpointBackup struct {
@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,6 +25,7 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // CheckpointRestoreSpec defines the desired state of CheckpointRestore
+// +kubebuilder:validation:XValidation:rule="!(has(self.statefulSet) && has(self.deployment))",message="statefulSet and deployment are mutually exclusive"
 type CheckpointRestoreSpec struct {
 	// BackupRef specifies the backup to restore from
 	// +required
@@ -36,16 +38,175 @@ type CheckpointRestoreSpec struct {
 	// Containers specifies the container configurations for restore
 	// +optional
 	Containers []Container `json:"containers,omitempty"`
+
+	// RestorePoint selects the checkpoint of the backup to restore from. Defaults to the latest checkpoint.
+	// +optional
+	RestorePoint *RestorePoint `json:"restorePoint,omitempty"`
+
+	// PodTemplate is the template the restored pod is built from.
+	// If unset, the template of the workload referenced by the backup is used.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	PodTemplate *corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+
+	// StatefulSet gives the restored pod the identity of a StatefulSet ordinal, so that the
+	// StatefulSet adopts it instead of creating the ordinal again
+	// +optional
+	StatefulSet *StatefulSetIdentity `json:"statefulSet,omitempty"`
+
+	// Deployment gives the restored pod the pod-template-hash of a ReplicaSet of the Deployment, so that
+	// the ReplicaSet adopts it instead of creating another replica
+	// +optional
+	Deployment *DeploymentIdentity `json:"deployment,omitempty"`
+}
+
+// StatefulSetIdentity describes the StatefulSet ordinal a restored pod takes over
+type StatefulSetIdentity struct {
+	// Name of the StatefulSet
+	// +required
+	Name string `json:"name"`
+
+	// Ordinal of the pod in the StatefulSet
+	// +required
+	// +kubebuilder:validation:Minimum=0
+	Ordinal int32 `json:"ordinal"`
+
+	// ServiceName is the governing service of the StatefulSet, used as the pod's subdomain
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+
+	// RevisionHash is the controller-revision-hash of the original pod. Keeping it stops the
+	// StatefulSet controller from replacing the restored pod with a pod of a new revision.
+	// +optional
+	RevisionHash string `json:"revisionHash,omitempty"`
+
+	// VolumeClaimTemplates of the StatefulSet. The claims of the ordinal are created if missing
+	// and mounted in the restored pod the way the StatefulSet controller would.
+	// +optional
+	VolumeClaimTemplates []corev1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
+}
+
+// DeploymentIdentity describes the ReplicaSet of a Deployment a restored pod is handed over to
+type DeploymentIdentity struct {
+	// Name of the Deployment
+	// +required
+	Name string `json:"name"`
+
+	// PodTemplateHash is the pod-template-hash of the original pod. It selects the ReplicaSet of the
+	// Deployment revision the pod belongs to, which the Deployment creates again on the target cluster.
+	// +required
+	PodTemplateHash string `json:"podTemplateHash"`
+}
+
+// RestorePointType selects how the checkpoint to restore from is chosen
+// +kubebuilder:validation:Enum=Latest;BeforeTime;Digest
+type RestorePointType string
+
+const (
+	// RestorePointLatest restores from the latest successful checkpoint
+	RestorePointLatest RestorePointType = "Latest"
+	// RestorePointBeforeTime restores from the newest checkpoint taken at or before RestorePoint.Time
+	RestorePointBeforeTime RestorePointType = "BeforeTime"
+	// RestorePointDigest restores from the checkpoint that pushed the image with RestorePoint.Digest
+	RestorePointDigest RestorePointType = "Digest"
+)
+
+// RestorePoint selects a checkpoint from the history of a CheckpointBackup
+// +kubebuilder:validation:XValidation:rule="self.type != 'BeforeTime' || has(self.time)",message="time is required for the BeforeTime restore point"
+// +kubebuilder:validation:XValidation:rule="self.type != 'Digest' || has(self.digest)",message="digest is required for the Digest restore point"
+type RestorePoint struct {
+	// Type of the restore point
+	// +optional
+	// +kubebuilder:default=Latest
+	Type RestorePointType `json:"type,omitempty"`
+
+	// Time restores the newest checkpoint taken at or before it, for the BeforeTime type
+	// +optional
+	Time *metav1.Time `json:"time,omitempty"`
+
+	// Digest of one of the checkpoint images of the checkpoint to restore, for the Digest type
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Digest string `json:"digest,omitempty"`
 }
 
+// RestorePointStatus records the checkpoint a CheckpointRestore restored from
+type RestorePointStatus struct {
+	// Type of the restore point that was requested
+	// +optional
+	Type RestorePointType `json:"type,omitempty"`
+
+	// CheckpointTime is when the restored checkpoint was taken
+	// +optional
+	CheckpointTime *metav1.Time `json:"checkpointTime,omitempty"`
+
+	// Containers records the checkpoint images of the restored checkpoint, with their digests
+	// +optional
+	Containers []ContainerCheckpoint `json:"containers,omitempty"`
+}
+
+// CheckpointRestorePhase is a label for the condition of a CheckpointRestore at the current time
+// +kubebuilder:validation:Enum=Pending;Restoring;Restored;Failed
+type CheckpointRestorePhase string
+
+const (
+	// CheckpointRestorePending means the checkpoint images to restore from are not known yet
+	CheckpointRestorePending CheckpointRestorePhase = "Pending"
+	// CheckpointRestoreRestoring means the pod has been created and the runtime is restoring its containers
+	CheckpointRestoreRestoring CheckpointRestorePhase = "Restoring"
+	// CheckpointRestoreRestored means the restored pod is running
+	CheckpointRestoreRestored CheckpointRestorePhase = "Restored"
+	// CheckpointRestoreFailed means the pod could not be restored
+	CheckpointRestoreFailed CheckpointRestorePhase = "Failed"
+)
+
+const (
+	// CheckpointRestoreConditionReady indicates whether the restored pod is running
+	CheckpointRestoreConditionReady = "Ready"
+)
+
 // CheckpointRestoreStatus defines the observed state of CheckpointRestore.
 type CheckpointRestoreStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Phase is the current phase of the CheckpointRestore
+	// +optional
+	Phase CheckpointRestorePhase `json:"phase,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the CheckpointRestore's state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// PodRef references the restored pod
+	// +optional
+	PodRef *PodRef `json:"podRef,omitempty"`
+
+	// Containers records the image each container was restored from, or whether it was skipped
+	// +optional
+	Containers []Container `json:"containers,omitempty"`
+
+	// RestorePoint records the checkpoint of the backup the pod was restored from
+	// +optional
+	RestorePoint *RestorePointStatus `json:"restorePoint,omitempty"`
+
+	// RestoreTime is when the restored pod started running
+	// +optional
+	RestoreTime *metav1.Time `json:"restoreTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Backup",type=string,JSONPath=`.spec.backupRef.name`
+// +kubebuilder:printcolumn:name="Pod",type=string,JSONPath=`.spec.podName`
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.metadata.labels.target-cluster`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // CheckpointRestore is the Schema for the checkpointrestores API
 type CheckpointRestore struct {
@@ -29,7 +29,8 @@ type ResourceRef struct {
 	// +required
 	APIVersion string `json:"apiVersion"`
 
-	// Kind of the referenced resource
+	// Kind of the referenced resource: Pod or any workload kind with a pod selector,
+	// such as StatefulSet, Deployment, ReplicaSet, DaemonSet or a custom resource with a scale subresource
 	// +required
 	Kind string `json:"kind"`
 
@@ -82,6 +83,34 @@ type Registry struct {
 	SecretRef *SecretRef `json:"secretRef,omitempty"`
 }
 
+// ContainerStrategy describes how a container is carried over to the restored pod
+// +kubebuilder:validation:Enum=Checkpoint;Recreate;Skip
+type ContainerStrategy string
+
+const (
+	// ContainerStrategyCheckpoint checkpoints the container and restores it from its checkpoint image
+	ContainerStrategyCheckpoint ContainerStrategy = "Checkpoint"
+
+	// ContainerStrategyRecreate does not checkpoint the container and starts it fresh from its original image
+	ContainerStrategyRecreate ContainerStrategy = "Recreate"
+
+	// ContainerStrategySkip does not checkpoint the container and leaves it out of the restored pod
+	ContainerStrategySkip ContainerStrategy = "Skip"
+)
+
+// ContainerRule sets the strategy of the containers matching any of its names
+type ContainerRule struct {
+	// Names of the containers the rule applies to. Shell patterns such as "istio-*" are allowed.
+	// +required
+	// +kubebuilder:validation:MinItems=1
+	Names []string `json:"names"`
+
+	// Strategy of the matching containers
+	// +optional
+	// +kubebuilder:default=Checkpoint
+	Strategy ContainerStrategy `json:"strategy,omitempty"`
+}
+
 // Container defines a container configuration for checkpoints
 type Container struct {
 	// Name of the container
@@ -91,17 +120,70 @@ type Container struct {
 	// Image of the container in the registry
 	// +required
 	Image string `json:"image"`
+
+	// Strategy of the container. Defaults to Checkpoint.
+	// +optional
+	Strategy ContainerStrategy `json:"strategy,omitempty"`
+}
+
+// ConcurrencyPolicy describes how a checkpoint is handled when the previous one is still running.
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent allows checkpoints to run concurrently
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+
+	// ForbidConcurrent skips the new checkpoint if the previous one hasn't finished yet
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+
+	// ReplaceConcurrent cancels the currently running checkpoint and replaces it with a new one
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// WorkloadSelector selects the workloads enrolled in a StatefulMigration
+type WorkloadSelector struct {
+	// LabelSelector selects the StatefulSets, Deployments and Pods to enroll by their labels.
+	// Pods are only enrolled if they are not controlled by another workload.
+	// +required
+	LabelSelector metav1.LabelSelector `json:"labelSelector"`
+
+	// NamespaceSelector selects the namespaces to enroll workloads from.
+	// Defaults to the namespace of the StatefulMigration.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 }
 
 // StatefulMigrationSpec defines the desired state of StatefulMigration
+// +kubebuilder:validation:XValidation:rule="has(self.resourceRef) != has(self.workloadSelector)",message="exactly one of resourceRef and workloadSelector must be set"
 type StatefulMigrationSpec struct {
 	// ResourceRef specifies the workload to migrate
-	// +required
-	ResourceRef ResourceRef `json:"resourceRef"`
+	// +optional
+	ResourceRef ResourceRef `json:"resourceRef,omitzero"`
 
-	// SourceClusters specifies which clusters to back up from
-	// +required
-	SourceClusters []string `json:"sourceClusters"`
+	// WorkloadSelector enrolls every workload matching the selector instead of the single ResourceRef.
+	// Workloads are enrolled and released as they start and stop matching.
+	// +optional
+	WorkloadSelector *WorkloadSelector `json:"workloadSelector,omitempty"`
+
+	// SourceClusters restricts the clusters to back up from. By default the workloads are backed up on every
+	// cluster Karmada scheduled them to, read from their ResourceBindings; workloads without a ResourceBinding
+	// are backed up on these clusters only.
+	// +optional
+	SourceClusters []string `json:"sourceClusters,omitempty"`
+
+	// TargetCluster specifies the cluster to migrate the workload to.
+	// Setting it starts a migration from the source clusters, which restores the
+	// workload's pods on the target from a final checkpoint and then moves the workload.
+	// +optional
+	TargetCluster string `json:"targetCluster,omitempty"`
+
+	// MigrationNamespace is the namespace created on Karmada and propagated to the source clusters.
+	// Defaults to the namespace set with the operator's --migration-namespace flag, stateful-migration.
+	// +optional
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	MigrationNamespace string `json:"migrationNamespace,omitempty"`
 
 	// Registry specifies the registry configuration for storing checkpoints
 	// +required
@@ -110,16 +192,391 @@ type StatefulMigrationSpec struct {
 	// Schedule specifies the backup schedule in cron format
 	// +required
 	Schedule string `json:"schedule"`
+
+	// StartingDeadlineSeconds is the deadline in seconds for starting a checkpoint
+	// that missed its scheduled time. Missed checkpoints older than the deadline are skipped.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// ConcurrencyPolicy specifies how to treat a scheduled checkpoint while the previous one is still running
+	// +optional
+	// +kubebuilder:default=Forbid
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Suspend tells the controller to suspend subsequent checkpoints.
+	// It does not apply to checkpoints that have already started.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// RemovedClusterPolicy selects what happens to the checkpoints of a cluster that stops being a source
+	// cluster, because it was removed from SourceClusters or Karmada moved the workloads away from it.
+	// Its CheckpointBackups are torn down either way.
+	// +optional
+	// +kubebuilder:default=Delete
+	RemovedClusterPolicy RemovedClusterPolicy `json:"removedClusterPolicy,omitempty"`
+
+	// Rollback specifies when and how a migration whose restored pods do not become healthy is rolled back
+	// +optional
+	Rollback *RollbackPolicy `json:"rollback,omitempty"`
+
+	// ContainerRules select how the containers of the workload's pods are checkpointed and restored.
+	// The first rule matching a container applies. Containers no rule matches, including native
+	// sidecars, are checkpointed. Init containers that run to completion cannot be checkpointed and
+	// run again in the restored pod unless a rule skips them.
+	// +optional
+	ContainerRules []ContainerRule `json:"containerRules,omitempty"`
+}
+
+// RemovedClusterPolicy selects what happens to the checkpoints of a cluster that stops being a source cluster
+// +kubebuilder:validation:Enum=Delete;Retain
+type RemovedClusterPolicy string
+
+const (
+	// RemovedClusterDelete tears down the CheckpointBackups of the cluster and forgets their checkpoint images
+	RemovedClusterDelete RemovedClusterPolicy = "Delete"
+
+	// RemovedClusterRetain tears down the CheckpointBackups of the cluster, but records the images of their
+	// last checkpoint in the status so that the pods can still be restored from them
+	RemovedClusterRetain RemovedClusterPolicy = "Retain"
+)
+
+// RollbackStrategy selects what the source clusters run after a migration is rolled back
+// +kubebuilder:validation:Enum=OriginalImages;PreviousCheckpoint
+type RollbackStrategy string
+
+const (
+	// RollbackOriginalImages restarts the workload on the source clusters from its own images
+	RollbackOriginalImages RollbackStrategy = "OriginalImages"
+
+	// RollbackPreviousCheckpoint restores the source pods from the last checkpoint taken before the
+	// final checkpoint of the migration
+	RollbackPreviousCheckpoint RollbackStrategy = "PreviousCheckpoint"
+)
+
+// RollbackPolicy describes when and how a migration is rolled back
+type RollbackPolicy struct {
+	// HealthTimeout is how long the restored pods have, from the start of the restore, to become
+	// ready on the target cluster before the migration is rolled back
+	// +optional
+	// +kubebuilder:default="10m"
+	HealthTimeout *metav1.Duration `json:"healthTimeout,omitempty"`
+
+	// Strategy selects what the source clusters run after a rollback. It only applies once the
+	// workload has been moved to the target cluster; before that, the source pods keep running.
+	// +optional
+	// +kubebuilder:default=OriginalImages
+	Strategy RollbackStrategy `json:"strategy,omitempty"`
+}
+
+// MigrationPhase is a step of a migration. Phases are run in the order they are declared in.
+// +kubebuilder:validation:Enum=Pending;Checkpointing;Restoring;SwitchingPlacement;Verifying;Completed;RollingBack;RolledBack;Failed
+type MigrationPhase string
+
+const (
+	// MigrationPending means the migration has been requested but not started yet
+	MigrationPending MigrationPhase = "Pending"
+	// MigrationCheckpointing means a final checkpoint of the source pods is being taken
+	MigrationCheckpointing MigrationPhase = "Checkpointing"
+	// MigrationRestoring means the pods are being restored on the target cluster from the final checkpoint
+	MigrationRestoring MigrationPhase = "Restoring"
+	// MigrationSwitchingPlacement means the workload's PropagationPolicy is being moved to the target cluster,
+	// which removes the source replicas
+	MigrationSwitchingPlacement MigrationPhase = "SwitchingPlacement"
+	// MigrationVerifying means the workload has been moved and the restored pods are checked to stay healthy
+	MigrationVerifying MigrationPhase = "Verifying"
+	// MigrationCompleted means the workload runs on the target cluster
+	MigrationCompleted MigrationPhase = "Completed"
+	// MigrationRollingBack means the restored pods did not become healthy and the workload is moved back to the source clusters
+	MigrationRollingBack MigrationPhase = "RollingBack"
+	// MigrationRolledBack means the workload runs on the source clusters again
+	MigrationRolledBack MigrationPhase = "RolledBack"
+	// MigrationFailed means the migration stopped before completing
+	MigrationFailed MigrationPhase = "Failed"
+)
+
+// MigrationStatus describes the progress of a migration to a target cluster
+type MigrationStatus struct {
+	// TargetCluster is the cluster the workload is migrated to
+	// +required
+	TargetCluster string `json:"targetCluster"`
+
+	// Phase is the current phase of the migration
+	// +optional
+	Phase MigrationPhase `json:"phase,omitempty"`
+
+	// Message is a human readable description of the current phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is when the migration started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CheckpointRequestTime is when the final checkpoint of the source pods was requested
+	// +optional
+	CheckpointRequestTime *metav1.Time `json:"checkpointRequestTime,omitempty"`
+
+	// RestoreStartTime is when the pods started being restored on the target cluster.
+	// The health timeout of the restored pods runs from it.
+	// +optional
+	RestoreStartTime *metav1.Time `json:"restoreStartTime,omitempty"`
+
+	// CompletionTime is when the migration completed, was rolled back or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Restores lists the CheckpointRestores created on the target cluster
+	// +optional
+	Restores []MigrationRestoreRef `json:"restores,omitempty"`
+
+	// SourcePlacements lists the clusters of the workloads' PropagationPolicies before they were moved
+	// to the target cluster. A rollback puts the placements back on them.
+	// +optional
+	SourcePlacements []PolicyPlacement `json:"sourcePlacements,omitempty"`
+
+	// RollbackReason explains why the migration was rolled back
+	// +optional
+	RollbackReason string `json:"rollbackReason,omitempty"`
+
+	// RollbackRestores lists the CheckpointRestores created on the source clusters by a rollback
+	// +optional
+	RollbackRestores []MigrationRestoreRef `json:"rollbackRestores,omitempty"`
+}
+
+// PolicyPlacement records the clusters a PropagationPolicy placed its resources on
+type PolicyPlacement struct {
+	// Namespace of the PropagationPolicy
+	// +required
+	Namespace string `json:"namespace"`
+
+	// Name of the PropagationPolicy
+	// +required
+	Name string `json:"name"`
+
+	// ClusterNames of the placement's cluster affinity
+	// +optional
+	ClusterNames []string `json:"clusterNames,omitempty"`
+}
+
+// MigrationRestoreRef references a CheckpointRestore created for a migration
+type MigrationRestoreRef struct {
+	// Name of the CheckpointRestore
+	// +required
+	Name string `json:"name"`
+
+	// Namespace of the CheckpointRestore
+	// +required
+	Namespace string `json:"namespace"`
+
+	// BackupName is the CheckpointBackup the restore was created from
+	// +required
+	BackupName string `json:"backupName"`
+
+	// Cluster is the member cluster the CheckpointRestore is propagated to
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// PodName is the name of the restored pod
+	// +required
+	PodName string `json:"podName"`
+
+	// Ordinal is the StatefulSet ordinal of the restored pod
+	// +optional
+	Ordinal *int32 `json:"ordinal,omitempty"`
+
+	// Ready indicates whether the restored pod is ready
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+}
+
+const (
+	// StatefulMigrationConditionReady indicates whether every pod of the workload has a healthy CheckpointBackup
+	// with a successful checkpoint on each source cluster
+	StatefulMigrationConditionReady = "Ready"
+	// StatefulMigrationConditionDegraded indicates whether some CheckpointBackups are failing or cannot be observed
+	StatefulMigrationConditionDegraded = "Degraded"
+	// StatefulMigrationConditionProgressing indicates whether some CheckpointBackups are waiting for their first checkpoint
+	StatefulMigrationConditionProgressing = "Progressing"
+	// StatefulMigrationConditionCompatible indicates whether the pre-flight checks found the source clusters,
+	// and the target cluster if set, able to checkpoint and restore the workload's pods
+	StatefulMigrationConditionCompatible = "Compatible"
+)
+
+// ClusterPreflight reports the checkpoint and restore capabilities of a member cluster found by the pre-flight checks
+type ClusterPreflight struct {
+	// Name of the member cluster
+	// +required
+	Name string `json:"name"`
+
+	// CheckTime is when the cluster was inspected
+	// +optional
+	CheckTime *metav1.Time `json:"checkTime,omitempty"`
+
+	// Architectures are the CPU architectures of the ready nodes
+	// +optional
+	Architectures []string `json:"architectures,omitempty"`
+
+	// KernelVersion is the oldest kernel version of the ready nodes
+	// +optional
+	KernelVersion string `json:"kernelVersion,omitempty"`
+
+	// ContainerRuntime is the container runtime of the ready nodes, such as cri-o or containerd
+	// +optional
+	ContainerRuntime string `json:"containerRuntime,omitempty"`
+
+	// ContainerRuntimeVersion is the oldest container runtime version of the ready nodes
+	// +optional
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion,omitempty"`
+
+	// CRIUVersion is the oldest CRIU version reported by the ready nodes, if any reports it
+	// +optional
+	CRIUVersion string `json:"criuVersion,omitempty"`
+
+	// CheckpointEnabled indicates whether the kubelet ContainerCheckpoint feature gate is enabled on every ready node
+	// whose kubelet could be inspected
+	// +optional
+	CheckpointEnabled bool `json:"checkpointEnabled,omitempty"`
+
+	// UnreachableNodes are the ready nodes whose kubelet could not be inspected, so whether they allow
+	// checkpoints is unknown
+	// +optional
+	UnreachableNodes []string `json:"unreachableNodes,omitempty"`
+
+	// Compatible indicates whether the cluster passed the pre-flight checks
+	// +optional
+	Compatible bool `json:"compatible,omitempty"`
+
+	// Message explains why the cluster did not pass the pre-flight checks, or which nodes could not be inspected
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PodBackupStatus summarizes the CheckpointBackup of a pod on a source cluster
+type PodBackupStatus struct {
+	// Namespace of the checkpointed pod
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// PodName is the name of the checkpointed pod
+	// +required
+	PodName string `json:"podName"`
+
+	// BackupName is the name of the CheckpointBackup
+	// +required
+	BackupName string `json:"backupName"`
+
+	// Phase is the phase of the CheckpointBackup reported by the agent on the cluster
+	// +optional
+	Phase CheckpointBackupPhase `json:"phase,omitempty"`
+
+	// LastSuccessfulTime is when the last successful checkpoint of the pod was taken
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// LastError is the last error of the CheckpointBackup, or why its status could not be read
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// ClusterBackupStatus summarizes the CheckpointBackups of a source cluster
+type ClusterBackupStatus struct {
+	// Name of the source cluster
+	// +required
+	Name string `json:"name"`
+
+	// Backups summarizes the CheckpointBackup of each pod on the cluster
+	// +optional
+	Backups []PodBackupStatus `json:"backups,omitempty"`
+}
+
+// SourceCluster is a cluster the workloads are backed up from
+type SourceCluster struct {
+	// Name of the cluster
+	// +required
+	Name string `json:"name"`
+
+	// Replicas is the number of replicas of the workloads Karmada scheduled to the cluster
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
 }
 
 // StatefulMigrationStatus defines the observed state of StatefulMigration.
 type StatefulMigrationStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// ObservedGeneration is the most recent generation observed by the backup controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the StatefulMigration's state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Workloads lists the workloads enrolled in the StatefulMigration
+	// +optional
+	Workloads []ResourceRef `json:"workloads,omitempty"`
+
+	// SourceClusters are the clusters the workloads are backed up from: the clusters of their ResourceBindings
+	// allowed by Spec.SourceClusters, which follow Karmada rescheduling the workloads
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	SourceClusters []SourceCluster `json:"sourceClusters,omitempty"`
+
+	// Preflight reports the result of the pre-flight checks of each source cluster and of the target cluster
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Preflight []ClusterPreflight `json:"preflight,omitempty"`
+
+	// Clusters summarizes the CheckpointBackups of each source cluster
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Clusters []ClusterBackupStatus `json:"clusters,omitempty"`
+
+	// RetainedCheckpoints records the last checkpoint of the pods of the clusters that stopped being source
+	// clusters, when Spec.RemovedClusterPolicy is Retain. They are dropped when the cluster is a source
+	// cluster again.
+	// +optional
+	RetainedCheckpoints []RetainedCheckpoint `json:"retainedCheckpoints,omitempty"`
+
+	// Migration reports the progress of the migration to Spec.TargetCluster
+	// +optional
+	Migration *MigrationStatus `json:"migration,omitempty"`
+}
+
+// RetainedCheckpoint is the last checkpoint of a pod of a cluster that stopped being a source cluster
+type RetainedCheckpoint struct {
+	// Cluster is the cluster the pod ran on
+	// +required
+	Cluster string `json:"cluster"`
+
+	// PodRef references the checkpointed pod
+	// +required
+	PodRef PodRef `json:"podRef"`
+
+	// ResourceRef references the workload of the pod
+	// +optional
+	ResourceRef ResourceRef `json:"resourceRef,omitempty"`
+
+	// Time is when the checkpoint was taken
+	// +required
+	Time metav1.Time `json:"time"`
+
+	// Containers records the checkpoint image of each container
+	// +optional
+	Containers []ContainerCheckpoint `json:"containers,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Compatible",type=string,JSONPath=`.status.conditions[?(@.type=="Compatible")].status`
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.targetCluster`
+// +kubebuilder:printcolumn:name="Migration",type=string,JSONPath=`.status.migration.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // StatefulMigration is the Schema for the statefulmigrations API
 type StatefulMigration struct {
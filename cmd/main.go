@@ -21,6 +21,7 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -37,12 +38,32 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
 	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
 	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
 	"github.com/lehuannhatrang/stateful-migration-operator/internal/controller"
+	webhookv1 "github.com/lehuannhatrang/stateful-migration-operator/internal/webhook/v1"
 	// +kubebuilder:scaffold:imports
 )
 
+const (
+	// modeAll runs every controller in a single manager
+	modeAll = "all"
+	// modeControlPlane runs the controllers that manage StatefulMigrations on the Karmada control plane
+	modeControlPlane = "control-plane"
+	// modeMember runs the checkpoint agent that acts on CheckpointBackups on a member cluster
+	modeMember = "member"
+	// modeMemberRestore runs the restorer that acts on CheckpointRestores on a member cluster. Unlike the
+	// per-node checkpoint agent, a single restorer must be active per cluster, so it is run with leader election.
+	modeMemberRestore = "member-restore"
+
+	// leaderElectionID is the lease of the control plane and of the managers running every controller
+	leaderElectionID = "0b77bf5f.dcnlab.com"
+	// restoreLeaderElectionID is the lease of the restorers, which must not compete with the other managers
+	restoreLeaderElectionID = "restore.0b77bf5f.dcnlab.com"
+)
+
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -53,6 +74,8 @@ func init() {
 
 	utilruntime.Must(migrationv1.AddToScheme(scheme))
 	utilruntime.Must(karmadav1alpha1.AddToScheme(scheme))
+	utilruntime.Must(clusterv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(workv1alpha2.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -65,6 +88,10 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var mode string
+	var checkpointTimeout, resyncPeriod time.Duration
+	var checkpointDir, nodeName, workloadMarker, migrationNamespace string
+	var karmadaKubeconfig, karmadaContext, karmadaSecret string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -83,6 +110,31 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&mode, "mode", modeAll,
+		"Which controllers to run: 'control-plane' for the Karmada control plane, "+
+			"'member' for the checkpoint agent on member clusters, 'member-restore' for the restorer on member clusters, "+
+			"or 'all' to run every controller.")
+	flag.DurationVar(&checkpointTimeout, "checkpoint-timeout", controller.DefaultCheckpointTimeout,
+		"The time the kubelet is given to checkpoint a single container.")
+	flag.DurationVar(&resyncPeriod, "resync-period", controller.DefaultResyncPeriod,
+		"How often a StatefulMigration is reconciled in case a workload or pod change was missed. 0 disables the periodic resync.")
+	flag.StringVar(&karmadaKubeconfig, "karmada-kubeconfig", controller.KarmadaKubeconfigPath,
+		"The kubeconfig of the Karmada API server. If it does not exist and the manager runs against "+
+			"the Karmada API server, the manager's own config is used.")
+	flag.StringVar(&karmadaContext, "karmada-context", "",
+		"The context of the Karmada kubeconfig to use. Defaults to its current context.")
+	flag.StringVar(&karmadaSecret, "karmada-kubeconfig-secret", "",
+		"A namespace/name reference to a Secret holding the Karmada kubeconfig under its 'kubeconfig' key, "+
+			"used instead of --karmada-kubeconfig.")
+	flag.StringVar(&migrationNamespace, "migration-namespace", controller.DefaultMigrationNamespace,
+		"The namespace created on Karmada and propagated to the source clusters of StatefulMigrations that do not set their own.")
+	flag.StringVar(&workloadMarker, "workload-marker", controller.WorkloadMarkerLabel,
+		"How enrolled workloads are marked: 'label', or 'annotation' for workloads managed by GitOps tools.")
+	flag.StringVar(&checkpointDir, "checkpoint-dir", "",
+		"The directory the node's kubelet checkpoint directory is mounted at. "+
+			"If empty, checkpoint archives are read from the path reported by the kubelet.")
+	flag.StringVar(&nodeName, "node-name", os.Getenv("NODE_NAME"),
+		"Only checkpoint pods running on this node. Set it when running the member agent as a DaemonSet.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -91,6 +143,20 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if mode != modeAll && mode != modeControlPlane && mode != modeMember && mode != modeMemberRestore {
+		setupLog.Error(nil, "invalid mode, must be one of 'all', 'control-plane', 'member' or 'member-restore'", "mode", mode)
+		os.Exit(1)
+	}
+	if _, err := controller.ParseWorkloadMarker(workloadMarker); err != nil {
+		setupLog.Error(err, "invalid workload marker")
+		os.Exit(1)
+	}
+	karmadaSecretRef, err := controller.ParseKarmadaSecretRef(karmadaSecret)
+	if err != nil {
+		setupLog.Error(err, "invalid Karmada kubeconfig Secret")
+		os.Exit(1)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -186,7 +252,7 @@ func main() {
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "0b77bf5f.dcnlab.com",
+		LeaderElectionID:       managerLeaderElectionID(mode),
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -204,26 +270,87 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := (&controller.CheckpointBackupReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "CheckpointBackup")
-		os.Exit(1)
+	if mode == modeAll || mode == modeMember {
+		kubeletClient, err := controller.NewKubeletClient(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create kubelet client")
+			os.Exit(1)
+		}
+		if err := (&controller.CheckpointBackupReconciler{
+			Client:            mgr.GetClient(),
+			Scheme:            mgr.GetScheme(),
+			KubeletClient:     kubeletClient,
+			RegistryClient:    controller.NewRegistryClient(),
+			Recorder:          mgr.GetEventRecorderFor("checkpointbackup-controller"),
+			CheckpointTimeout: checkpointTimeout,
+			CheckpointDir:     checkpointDir,
+			NodeName:          nodeName,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CheckpointBackup")
+			os.Exit(1)
+		}
 	}
-	if err := (&controller.MigrationBackupReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "MigrationBackup")
-		os.Exit(1)
+	// The checkpoint agent runs on every node, so the restores are reconciled by a separate, single restorer
+	if mode == modeAll || mode == modeMemberRestore {
+		if err := (&controller.CheckpointRestoreReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("checkpointrestore-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CheckpointRestore")
+			os.Exit(1)
+		}
 	}
-	if err := (&controller.MigrationRestoreReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "MigrationRestore")
-		os.Exit(1)
+	if mode == modeAll || mode == modeControlPlane {
+		// The Karmada clients are built once the manager starts and rebuilt when the credentials rotate
+		karmada := controller.NewKarmadaConnection(controller.KarmadaConnectionOptions{
+			Kubeconfig:    karmadaKubeconfig,
+			Context:       karmadaContext,
+			SecretRef:     karmadaSecretRef,
+			Reader:        mgr.GetAPIReader(),
+			ManagerConfig: mgr.GetConfig(),
+		})
+		if err := mgr.Add(karmada); err != nil {
+			setupLog.Error(err, "unable to set up the Karmada connection")
+			os.Exit(1)
+		}
+		if err := (&controller.MigrationBackupReconciler{
+			Client:             mgr.GetClient(),
+			Scheme:             mgr.GetScheme(),
+			Recorder:           mgr.GetEventRecorderFor("migrationbackup-controller"),
+			Karmada:            karmada,
+			ResyncPeriod:       resyncPeriod,
+			WorkloadMarker:     workloadMarker,
+			MigrationNamespace: migrationNamespace,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "MigrationBackup")
+			os.Exit(1)
+		}
+		if err := (&controller.MigrationRestoreReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("migrationrestore-controller"),
+			Karmada:  karmada,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "MigrationRestore")
+			os.Exit(1)
+		}
+		// The webhooks validate against the Karmada Clusters, so they are served by the control plane only
+		// nolint:goconst
+		if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+			if err := webhookv1.SetupStatefulMigrationWebhookWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "StatefulMigration")
+				os.Exit(1)
+			}
+			if err := webhookv1.SetupCheckpointBackupWebhookWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "CheckpointBackup")
+				os.Exit(1)
+			}
+			if err := webhookv1.SetupCheckpointRestoreWebhookWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "CheckpointRestore")
+				os.Exit(1)
+			}
+		}
 	}
 	// +kubebuilder:scaffold:builder
 
@@ -258,3 +385,11 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// managerLeaderElectionID returns the leader election lease of the manager running in the given mode
+func managerLeaderElectionID(mode string) string {
+	if mode == modeMemberRestore {
+		return restoreLeaderElectionID
+	}
+	return leaderElectionID
+}
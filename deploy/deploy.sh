@@ -110,6 +110,34 @@ else
     print_success "All required CRDs found"
 fi
 
+# Generate the serving certificate of the admission webhooks once
+WEBHOOK_SERVICE="migration-backup-controller-webhook"
+WEBHOOK_SECRET="migration-backup-controller-webhook-cert"
+print_status "Checking webhook certificate..."
+kubectl create namespace "$NAMESPACE" --dry-run=client -o yaml | kubectl apply -f - >/dev/null
+if ! kubectl get secret "$WEBHOOK_SECRET" -n "$NAMESPACE" >/dev/null 2>&1; then
+    if ! command -v openssl &> /dev/null; then
+        print_error "openssl is required to generate the webhook certificate"
+        exit 1
+    fi
+    CERT_DIR=$(mktemp -d)
+    openssl req -x509 -newkey rsa:2048 -nodes -days 3650 -subj "/CN=migration-webhook-ca" \
+        -keyout "$CERT_DIR/ca.key" -out "$CERT_DIR/ca.crt" 2>/dev/null
+    openssl req -newkey rsa:2048 -nodes -subj "/CN=$WEBHOOK_SERVICE.$NAMESPACE.svc" \
+        -keyout "$CERT_DIR/tls.key" -out "$CERT_DIR/tls.csr" 2>/dev/null
+    printf "subjectAltName=DNS:%s.%s.svc,DNS:%s.%s.svc.cluster.local" \
+        "$WEBHOOK_SERVICE" "$NAMESPACE" "$WEBHOOK_SERVICE" "$NAMESPACE" > "$CERT_DIR/san.ext"
+    openssl x509 -req -days 3650 -in "$CERT_DIR/tls.csr" -CA "$CERT_DIR/ca.crt" -CAkey "$CERT_DIR/ca.key" \
+        -CAcreateserial -extfile "$CERT_DIR/san.ext" -out "$CERT_DIR/tls.crt" 2>/dev/null
+    kubectl create secret generic "$WEBHOOK_SECRET" -n "$NAMESPACE" \
+        --from-file=tls.crt="$CERT_DIR/tls.crt" --from-file=tls.key="$CERT_DIR/tls.key" \
+        --from-file=ca.crt="$CERT_DIR/ca.crt"
+    rm -rf "$CERT_DIR"
+    print_success "Webhook certificate generated"
+else
+    print_success "Webhook certificate found"
+fi
+
 # Create temporary deployment file with correct image
 print_status "Preparing deployment manifests..."
 TEMP_FILE=$(mktemp)
@@ -143,6 +171,16 @@ else
     exit 1
 fi
 
+# Register the admission webhooks once the controller serves them
+print_status "Registering admission webhooks..."
+CA_BUNDLE=$(kubectl get secret "$WEBHOOK_SECRET" -n "$NAMESPACE" -o jsonpath='{.data.ca\.crt}')
+if sed "s|REPLACE_WITH_BASE64_ENCODED_CA_BUNDLE|$CA_BUNDLE|g" webhook.yaml | kubectl apply -f -; then
+    print_success "Admission webhooks registered"
+else
+    print_error "Failed to register admission webhooks"
+    exit 1
+fi
+
 # Check pod status
 print_status "Checking pod status..."
 kubectl get pods -n "$NAMESPACE" -l app.kubernetes.io/name=migration-backup-controller
@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// Reasons used in the StatefulMigration Ready, Degraded and Progressing conditions
+const (
+	ReasonBackupsReady          = "BackupsReady"
+	ReasonBackupsNotReady       = "BackupsNotReady"
+	ReasonNoBackups             = "NoBackups"
+	ReasonBackupsHealthy        = "BackupsHealthy"
+	ReasonBackupsFailed         = "BackupsFailed"
+	ReasonClusterIncompatible   = "ClusterIncompatible"
+	ReasonWaitingForCheckpoints = "WaitingForCheckpoints"
+	ReasonCheckpointsTaken      = "CheckpointsTaken"
+)
+
+const (
+	// conditionMessagePods is the number of pods named in a condition message, the others are only counted
+	conditionMessagePods = 3
+	// conditionMessageErrorLength is the length the error of a pod is truncated to in a condition message
+	conditionMessageErrorLength = 256
+)
+
+// podBackupStatus summarizes a CheckpointBackup as reported by the agent on its cluster
+func podBackupStatus(backup *migrationv1.CheckpointBackup) migrationv1.PodBackupStatus {
+	summary := migrationv1.PodBackupStatus{
+		Namespace:          backup.Spec.PodRef.Namespace,
+		PodName:            backup.Spec.PodRef.Name,
+		BackupName:         backup.Name,
+		Phase:              backup.Status.Phase,
+		LastSuccessfulTime: backup.Status.LastSuccessfulTime,
+	}
+
+	// The Ready condition explains a failed checkpoint, the Scheduled condition an unusable schedule
+	for _, conditionType := range []string{migrationv1.CheckpointBackupConditionReady, migrationv1.CheckpointBackupConditionScheduled} {
+		if condition := meta.FindStatusCondition(backup.Status.Conditions, conditionType); condition != nil && condition.Status == metav1.ConditionFalse {
+			summary.LastError = condition.Message
+			break
+		}
+	}
+	return summary
+}
+
+// isBackupFailing reports whether the CheckpointBackup failed or its status could not be read
+func isBackupFailing(backup *migrationv1.PodBackupStatus) bool {
+	return backup.Phase == migrationv1.CheckpointBackupFailed || (backup.Phase == "" && backup.LastError != "")
+}
+
+// backupConditions evaluates the backup summary and the pre-flight results of the StatefulMigration
+// into its Ready, Degraded and Progressing conditions
+func backupConditions(statefulMigration *migrationv1.StatefulMigration) []metav1.Condition {
+	var total int
+	var failing, waiting []string
+	for _, cluster := range statefulMigration.Status.Clusters {
+		for i := range cluster.Backups {
+			backup := &cluster.Backups[i]
+			total++
+			switch {
+			case isBackupFailing(backup):
+				failing = append(failing, fmt.Sprintf("%s on cluster %s: %s", backup.PodName, cluster.Name, truncateMessage(backup.LastError, conditionMessageErrorLength)))
+			case backup.LastSuccessfulTime == nil:
+				waiting = append(waiting, fmt.Sprintf("%s on cluster %s", backup.PodName, cluster.Name))
+			}
+		}
+	}
+
+	var incompatible []string
+	for _, preflight := range statefulMigration.Status.Preflight {
+		if !preflight.Compatible && slices.Contains(sourceClusters(statefulMigration), preflight.Name) {
+			incompatible = append(incompatible, preflight.Name)
+		}
+	}
+
+	ready := metav1.Condition{
+		Type:               migrationv1.StatefulMigrationConditionReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: statefulMigration.Generation,
+		Reason:             ReasonBackupsReady,
+		Message:            fmt.Sprintf("%d CheckpointBackup(s) have a successful checkpoint", total),
+	}
+	switch {
+	case total == 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = ReasonNoBackups
+		ready.Message = "no CheckpointBackups have been created"
+	case len(failing) > 0 || len(waiting) > 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = ReasonBackupsNotReady
+		ready.Message = fmt.Sprintf("%d of %d CheckpointBackup(s) have a successful checkpoint", total-len(failing)-len(waiting), total)
+	}
+
+	degraded := metav1.Condition{
+		Type:               migrationv1.StatefulMigrationConditionDegraded,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: statefulMigration.Generation,
+		Reason:             ReasonBackupsHealthy,
+		Message:            "no CheckpointBackups are failing",
+	}
+	switch {
+	case len(failing) > 0:
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = ReasonBackupsFailed
+		degraded.Message = fmt.Sprintf("%d CheckpointBackup(s) are failing: %s", len(failing), summarizePods(failing, "; "))
+	case len(incompatible) > 0:
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = ReasonClusterIncompatible
+		degraded.Message = fmt.Sprintf("source cluster(s) %s failed the pre-flight checks", strings.Join(incompatible, ", "))
+	}
+
+	progressing := metav1.Condition{
+		Type:               migrationv1.StatefulMigrationConditionProgressing,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: statefulMigration.Generation,
+		Reason:             ReasonCheckpointsTaken,
+		Message:            "no CheckpointBackups are waiting for their first checkpoint",
+	}
+	if len(waiting) > 0 {
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = ReasonWaitingForCheckpoints
+		progressing.Message = fmt.Sprintf("waiting for the first checkpoint of %d pod(s): %s", len(waiting), summarizePods(waiting, ", "))
+	}
+
+	return []metav1.Condition{ready, degraded, progressing}
+}
+
+// summarizePods joins the first conditionMessagePods pods of a condition message and counts the others,
+// which are detailed in status.clusters, so that the message stays within the size allowed for conditions
+func summarizePods(pods []string, separator string) string {
+	if len(pods) <= conditionMessagePods {
+		return strings.Join(pods, separator)
+	}
+	return fmt.Sprintf("%s%sand %d more in status.clusters", strings.Join(pods[:conditionMessagePods], separator), separator, len(pods)-conditionMessagePods)
+}
+
+// truncateMessage shortens a message to at most length bytes
+func truncateMessage(message string, length int) string {
+	if len(message) <= length {
+		return message
+	}
+	return strings.ToValidUTF8(message[:length-3], "") + "..."
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+var _ = Describe("Backup summary", func() {
+	lastSuccessfulTime := metav1.Now()
+
+	newStatefulMigration := func(backups ...migrationv1.PodBackupStatus) *migrationv1.StatefulMigration {
+		return &migrationv1.StatefulMigration{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec: migrationv1.StatefulMigrationSpec{
+				SourceClusters: []string{"member1"},
+			},
+			Status: migrationv1.StatefulMigrationStatus{
+				Clusters: []migrationv1.ClusterBackupStatus{{Name: "member1", Backups: backups}},
+			},
+		}
+	}
+
+	conditionStatus := func(conditions []metav1.Condition, conditionType string) metav1.ConditionStatus {
+		condition := meta.FindStatusCondition(conditions, conditionType)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+		return condition.Status
+	}
+
+	Context("When summarizing a CheckpointBackup", func() {
+		It("should report the last error from the conditions", func() {
+			backup := &migrationv1.CheckpointBackup{
+				ObjectMeta: metav1.ObjectMeta{Name: "migration-web-0-member1"},
+				Spec: migrationv1.CheckpointBackupSpec{
+					PodRef: migrationv1.PodRef{Namespace: "default", Name: "web-0"},
+				},
+				Status: migrationv1.CheckpointBackupStatus{
+					Phase:              migrationv1.CheckpointBackupFailed,
+					LastSuccessfulTime: &lastSuccessfulTime,
+					Conditions: []metav1.Condition{
+						{Type: migrationv1.CheckpointBackupConditionScheduled, Status: metav1.ConditionTrue, Reason: ReasonScheduleActive},
+						{Type: migrationv1.CheckpointBackupConditionReady, Status: metav1.ConditionFalse, Reason: ReasonUploadFailed, Message: "registry unavailable"},
+					},
+				},
+			}
+
+			summary := podBackupStatus(backup)
+			Expect(summary.PodName).To(Equal("web-0"))
+			Expect(summary.BackupName).To(Equal("migration-web-0-member1"))
+			Expect(summary.Phase).To(Equal(migrationv1.CheckpointBackupFailed))
+			Expect(summary.LastSuccessfulTime).To(Equal(&lastSuccessfulTime))
+			Expect(summary.LastError).To(Equal("registry unavailable"))
+		})
+	})
+
+	Context("When evaluating the conditions", func() {
+		It("should be ready once every pod has a successful checkpoint", func() {
+			conditions := backupConditions(newStatefulMigration(
+				migrationv1.PodBackupStatus{PodName: "web-0", Phase: migrationv1.CheckpointBackupScheduled, LastSuccessfulTime: &lastSuccessfulTime},
+				migrationv1.PodBackupStatus{PodName: "web-1", Phase: migrationv1.CheckpointBackupSucceeded, LastSuccessfulTime: &lastSuccessfulTime},
+			))
+
+			Expect(conditionStatus(conditions, migrationv1.StatefulMigrationConditionReady)).To(Equal(metav1.ConditionTrue))
+			Expect(conditionStatus(conditions, migrationv1.StatefulMigrationConditionDegraded)).To(Equal(metav1.ConditionFalse))
+			Expect(conditionStatus(conditions, migrationv1.StatefulMigrationConditionProgressing)).To(Equal(metav1.ConditionFalse))
+		})
+
+		It("should be progressing while pods wait for their first checkpoint", func() {
+			conditions := backupConditions(newStatefulMigration(
+				migrationv1.PodBackupStatus{PodName: "web-0", Phase: migrationv1.CheckpointBackupScheduled, LastSuccessfulTime: &lastSuccessfulTime},
+				migrationv1.PodBackupStatus{PodName: "web-1", Phase: migrationv1.CheckpointBackupScheduled},
+			))
+
+			Expect(conditionStatus(conditions, migrationv1.StatefulMigrationConditionReady)).To(Equal(metav1.ConditionFalse))
+			Expect(conditionStatus(conditions, migrationv1.StatefulMigrationConditionDegraded)).To(Equal(metav1.ConditionFalse))
+			Expect(conditionStatus(conditions, migrationv1.StatefulMigrationConditionProgressing)).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("should be degraded when a backup fails or cannot be observed", func() {
+			statefulMigration := newStatefulMigration(
+				migrationv1.PodBackupStatus{PodName: "web-0", LastError: "failed to read status from cluster member1: timeout"},
+			)
+			conditions := backupConditions(statefulMigration)
+			Expect(conditionStatus(conditions, migrationv1.StatefulMigrationConditionReady)).To(Equal(metav1.ConditionFalse))
+			Expect(conditionStatus(conditions, migrationv1.StatefulMigrationConditionDegraded)).To(Equal(metav1.ConditionTrue))
+			Expect(meta.FindStatusCondition(conditions, migrationv1.StatefulMigrationConditionDegraded).Message).To(ContainSubstring("timeout"))
+
+			statefulMigration.Status.Clusters = []migrationv1.ClusterBackupStatus{{Name: "member1"}}
+			statefulMigration.Status.Preflight = []migrationv1.ClusterPreflight{{Name: "member1", Message: "no ready nodes"}}
+			conditions = backupConditions(statefulMigration)
+			Expect(meta.FindStatusCondition(conditions, migrationv1.StatefulMigrationConditionReady).Reason).To(Equal(ReasonNoBackups))
+			Expect(meta.FindStatusCondition(conditions, migrationv1.StatefulMigrationConditionDegraded).Reason).To(Equal(ReasonClusterIncompatible))
+		})
+
+		It("should name only the first pods in the messages", func() {
+			var backups []migrationv1.PodBackupStatus
+			for i := range 500 {
+				backups = append(backups,
+					migrationv1.PodBackupStatus{PodName: fmt.Sprintf("web-%d", i), Phase: migrationv1.CheckpointBackupFailed, LastError: strings.Repeat("x", 4096)},
+					migrationv1.PodBackupStatus{PodName: fmt.Sprintf("db-%d", i), Phase: migrationv1.CheckpointBackupScheduled},
+				)
+			}
+			conditions := backupConditions(newStatefulMigration(backups...))
+
+			degraded := meta.FindStatusCondition(conditions, migrationv1.StatefulMigrationConditionDegraded)
+			Expect(degraded.Message).To(HavePrefix("500 CheckpointBackup(s) are failing: web-0 on cluster member1: xxx"))
+			Expect(degraded.Message).To(HaveSuffix("; and 497 more in status.clusters"))
+			Expect(len(degraded.Message)).To(BeNumerically("<", 1024))
+
+			progressing := meta.FindStatusCondition(conditions, migrationv1.StatefulMigrationConditionProgressing)
+			Expect(progressing.Message).To(Equal("waiting for the first checkpoint of 500 pod(s): db-0 on cluster member1, db-1 on cluster member1, db-2 on cluster member1, and 497 more in status.clusters"))
+		})
+	})
+})
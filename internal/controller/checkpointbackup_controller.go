@@ -18,46 +18,582 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	goruntime "runtime"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
 )
 
+const (
+	// DefaultCheckpointTimeout is the time the kubelet is given to checkpoint a single container
+	DefaultCheckpointTimeout = 4 * time.Minute
+
+	// CheckpointRequestAnnotation requests an immediate checkpoint outside of the schedule.
+	// Its value is an RFC 3339 timestamp; the request is served once its time is after the last schedule time.
+	CheckpointRequestAnnotation = "migration.dcnlab.com/checkpoint-requested"
+
+	// checkpointRequestClockSkew is how far ahead of the member cluster clock a checkpoint request may be stamped
+	// by the control plane and still be served immediately
+	checkpointRequestClockSkew = 30 * time.Second
+
+	// DefaultCheckpointHistoryLimit is the number of checkpoints kept in the status history if Spec.HistoryLimit is unset
+	DefaultCheckpointHistoryLimit = 10
+
+	// maxImageTagLength is the longest tag an OCI registry accepts
+	maxImageTagLength = 128
+)
+
+// Reasons used in CheckpointBackup conditions
+const (
+	ReasonScheduleActive         = "ScheduleActive"
+	ReasonInvalidSchedule        = "InvalidSchedule"
+	ReasonTooManyMissedSchedules = "TooManyMissedSchedules"
+	ReasonSuspended              = "Suspended"
+	ReasonPodNotRunning          = "PodNotRunning"
+	ReasonCheckpointFailed       = "CheckpointFailed"
+	ReasonUploadFailed           = "UploadFailed"
+	ReasonCheckpointSucceeded    = "CheckpointSucceeded"
+)
+
 // CheckpointBackupReconciler reconciles a CheckpointBackup object
 type CheckpointBackupReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme            *runtime.Scheme
+	KubeletClient     *KubeletClient
+	RegistryClient    *RegistryClient
+	Recorder          record.EventRecorder
+	CheckpointTimeout time.Duration
+	// CheckpointDir is the local directory the node's kubelet checkpoint directory is mounted at.
+	// If empty, archives are read from the path returned by the kubelet.
+	CheckpointDir string
+	// NodeName restricts the reconciler to pods running on this node, so that one agent per node
+	// can read the checkpoint archives written by its kubelet. If empty, every pod is handled.
+	NodeName string
+
+	// runs tracks the checkpoints running in the background
+	runs *checkpointRunTracker
+	// now returns the current time, overridable for tests
+	now func() time.Time
 }
 
 // +kubebuilder:rbac:groups=migration.dcnlab.com,resources=checkpointbackups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=migration.dcnlab.com,resources=checkpointbackups/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=migration.dcnlab.com,resources=checkpointbackups/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get
+// +kubebuilder:rbac:groups="",resources=nodes/proxy,verbs=get;create
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the CheckpointBackup object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
-//
-// For more details, check Reconcile and its Result here:
-// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+// It evaluates the cron schedule of the CheckpointBackup, starts a checkpoint of
+// the referenced pod when a schedule time is due and requeues at the next one.
 func (r *CheckpointBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = logf.FromContext(ctx)
+	log := logf.FromContext(ctx)
+
+	if r.runs == nil {
+		r.runs = newCheckpointRunTracker()
+	}
+
+	// Fetch the CheckpointBackup instance
+	var backup migrationv1.CheckpointBackup
+	if err := r.Get(ctx, req.NamespacedName, &backup); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("CheckpointBackup resource not found. Ignoring since object must be deleted")
+			r.runs.cancel(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get CheckpointBackup")
+		return ctrl.Result{}, err
+	}
+
+	if backup.GetDeletionTimestamp() != nil {
+		r.runs.cancel(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	// When running as a per-node agent, only handle pods scheduled on this node
+	if r.NodeName != "" {
+		pod, err := r.getTargetPod(ctx, &backup)
+		if err != nil || pod.Spec.NodeName != r.NodeName {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+	}
+
+	// Status changes are collected on the fetched object and written once through the status subresource
+	originalStatus := backup.Status.DeepCopy()
+	backup.Status.ObservedGeneration = backup.Generation
+
+	sched, err := parseSchedule(backup.Spec.Schedule)
+	if err != nil {
+		// An invalid schedule can only be fixed by updating the spec, so don't requeue
+		log.Error(err, "Failed to parse schedule", "schedule", backup.Spec.Schedule)
+		backup.Status.Phase = migrationv1.CheckpointBackupFailed
+		backup.Status.NextScheduleTime = nil
+		setCheckpointBackupCondition(&backup, migrationv1.CheckpointBackupConditionScheduled, metav1.ConditionFalse, ReasonInvalidSchedule, err.Error())
+		return ctrl.Result{}, r.updateStatusIfChanged(ctx, &backup, originalStatus)
+	}
+
+	now := r.clock()
+	var missedRun, nextRun time.Time
+	scheduledResult := ctrl.Result{}
+
+	if backup.Spec.Suspend != nil && *backup.Spec.Suspend {
+		log.Info("CheckpointBackup is suspended, skipping scheduled checkpoints")
+		backup.Status.NextScheduleTime = nil
+		setCheckpointBackupCondition(&backup, migrationv1.CheckpointBackupConditionScheduled, metav1.ConditionFalse, ReasonSuspended, "Scheduled checkpoints are suspended")
+	} else {
+		// Work out the schedule time that is due, if any, and the next one
+		earliestTime := backup.CreationTimestamp.Time
+		if backup.Status.LastScheduleTime != nil {
+			earliestTime = backup.Status.LastScheduleTime.Time
+		}
+		missedRun, nextRun, err = getNextSchedule(sched, earliestTime, now, backup.Spec.StartingDeadlineSeconds)
+		if err != nil {
+			log.Error(err, "Failed to compute next schedule time")
+			backup.Status.Phase = migrationv1.CheckpointBackupFailed
+			backup.Status.NextScheduleTime = nil
+			setCheckpointBackupCondition(&backup, migrationv1.CheckpointBackupConditionScheduled, metav1.ConditionFalse, ReasonTooManyMissedSchedules, err.Error())
+			return ctrl.Result{}, r.updateStatusIfChanged(ctx, &backup, originalStatus)
+		}
+		scheduledResult.RequeueAfter = nextRun.Sub(now)
+
+		// A backup that was never scheduled, or could not be scheduled before, is now waiting for its next run
+		if backup.Status.Phase == "" || !meta.IsStatusConditionTrue(originalStatus.Conditions, migrationv1.CheckpointBackupConditionScheduled) {
+			backup.Status.Phase = migrationv1.CheckpointBackupScheduled
+		}
+		backup.Status.NextScheduleTime = &metav1.Time{Time: nextRun}
+		setCheckpointBackupCondition(&backup, migrationv1.CheckpointBackupConditionScheduled, metav1.ConditionTrue, ReasonScheduleActive, "Checkpoints are scheduled")
+	}
+
+	// A requested checkpoint runs even when the backup is suspended and takes the place of a missed schedule
+	requested, untilRequested := checkpointRequestTime(&backup, now)
+	switch {
+	case untilRequested > 0:
+		log.Info("Checkpoint requested ahead of the local clock, waiting for it", "requestTime", requested)
+		if scheduledResult.RequeueAfter == 0 || untilRequested < scheduledResult.RequeueAfter {
+			scheduledResult.RequeueAfter = untilRequested
+		}
+	case !requested.IsZero():
+		log.Info("Checkpoint requested", "requestTime", requested)
+		missedRun = requested
+	}
+
+	startRun := false
+	if !missedRun.IsZero() && !r.runs.isRunning(req.NamespacedName, missedRun) {
+		switch {
+		case backup.Spec.ConcurrencyPolicy == migrationv1.AllowConcurrent:
+			startRun = true
+		case backup.Spec.ConcurrencyPolicy == migrationv1.ReplaceConcurrent:
+			if r.runs.active(req.NamespacedName) > 0 {
+				log.Info("Replacing running checkpoint", "scheduledTime", missedRun)
+				r.runs.cancel(req.NamespacedName)
+			}
+			startRun = true
+		default:
+			// Forbid is the default: wait for the running checkpoint to finish.
+			// The missed run stays due until it passes startingDeadlineSeconds.
+			if active := r.runs.active(req.NamespacedName); active > 0 {
+				log.Info("Checkpoint still running, skipping scheduled checkpoint", "scheduledTime", missedRun, "active", active)
+			} else {
+				startRun = true
+			}
+		}
+	}
+
+	if startRun {
+		backup.Status.LastScheduleTime = &metav1.Time{Time: missedRun}
+		backup.Status.Phase = migrationv1.CheckpointBackupCheckpointing
+	}
 
-	// TODO(user): your logic here
+	if err := r.updateStatusIfChanged(ctx, &backup, originalStatus); err != nil {
+		return ctrl.Result{}, err
+	}
 
-	return ctrl.Result{}, nil
+	if startRun {
+		log.Info("Starting checkpoint", "scheduledTime", missedRun, "nextScheduleTime", nextRun)
+		runLog := log.WithValues("scheduledTime", missedRun)
+		r.runs.start(req.NamespacedName, missedRun, func(runCtx context.Context) {
+			r.runCheckpoint(logf.IntoContext(runCtx, runLog), req.NamespacedName)
+		})
+	}
+
+	return scheduledResult, nil
+}
+
+// checkpointRequestTime returns the time of the checkpoint requested through the CheckpointRequestAnnotation,
+// or the zero time if there is no request or it has already been scheduled.
+// The request is stamped with the control plane clock: it is due once now is within checkpointRequestClockSkew
+// of it, and the returned duration is how long until then for a request that is not due yet.
+func checkpointRequestTime(backup *migrationv1.CheckpointBackup, now time.Time) (time.Time, time.Duration) {
+	value, ok := backup.Annotations[CheckpointRequestAnnotation]
+	if !ok {
+		return time.Time{}, 0
+	}
+
+	requested, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	if backup.Status.LastScheduleTime != nil && !requested.After(backup.Status.LastScheduleTime.Time) {
+		return time.Time{}, 0
+	}
+	return requested, requested.Sub(now) - checkpointRequestClockSkew
+}
+
+// checkpointImageTag returns the tag of the checkpoint image of a container, <pod>-<container>-<time>.
+// Pod and container names that would make the tag longer than an OCI registry accepts are truncated
+// and suffixed with a hash of the full names, so that tags stay unique.
+func checkpointImageTag(podName, containerName string, checkpointTime time.Time) string {
+	name := podName + "-" + containerName
+	timestamp := checkpointTime.UTC().Format("20060102150405")
+	if maxName := maxImageTagLength - len(timestamp) - 1; len(name) > maxName {
+		sum := sha256.Sum256([]byte(name))
+		hash := hex.EncodeToString(sum[:])[:8]
+		name = name[:maxName-len(hash)-1] + "-" + hash
+	}
+	return name + "-" + timestamp
+}
+
+// runCheckpoint checkpoints the pod referenced by the CheckpointBackup and records the result on its status
+func (r *CheckpointBackupReconciler) runCheckpoint(ctx context.Context, key types.NamespacedName) {
+	log := logf.FromContext(ctx)
+
+	var backup migrationv1.CheckpointBackup
+	if err := r.Get(ctx, key, &backup); err != nil {
+		log.Error(err, "Failed to get CheckpointBackup for checkpoint run")
+		return
+	}
+
+	// Resolve the pod to checkpoint
+	pod, err := r.getTargetPod(ctx, &backup)
+	if err != nil {
+		log.Error(err, "Failed to get pod to checkpoint", "pod", backup.Spec.PodRef.Name)
+		r.recordRunResult(ctx, &backup, migrationv1.CheckpointBackupPending, ReasonPodNotRunning,
+			fmt.Sprintf("Failed to get pod %s: %v", backup.Spec.PodRef.Name, err))
+		return
+	}
+
+	if pod.Spec.NodeName == "" || pod.Status.Phase != corev1.PodRunning {
+		log.Info("Pod is not running, skipping checkpoint", "pod", pod.Name, "phase", pod.Status.Phase)
+		r.recordRunResult(ctx, &backup, migrationv1.CheckpointBackupPending, ReasonPodNotRunning,
+			fmt.Sprintf("Pod %s is not running (phase %s)", pod.Name, pod.Status.Phase))
+		return
+	}
+
+	// Checkpoint the containers of the pod
+	checkpoints, err := r.checkpointPod(ctx, &backup, pod)
+	if err != nil {
+		log.Error(err, "Failed to checkpoint pod", "pod", pod.Name, "node", pod.Spec.NodeName)
+		r.recordRunResult(ctx, &backup, migrationv1.CheckpointBackupFailed, ReasonCheckpointFailed,
+			fmt.Sprintf("Failed to checkpoint pod %s: %v", pod.Name, err))
+		return
+	}
+
+	if err := r.updateRunStatus(ctx, key, func(backup *migrationv1.CheckpointBackup) {
+		backup.Status.Phase = migrationv1.CheckpointBackupUploading
+	}); err != nil {
+		log.Error(err, "Failed to update CheckpointBackup status")
+	}
+
+	// Push the checkpoint archives to the registry as checkpoint images
+	if err := r.pushCheckpoints(ctx, &backup, pod, checkpoints); err != nil {
+		log.Error(err, "Failed to push checkpoint images", "pod", pod.Name, "registry", backup.Spec.Registry.URL)
+		r.recordRunResult(ctx, &backup, migrationv1.CheckpointBackupFailed, ReasonUploadFailed,
+			fmt.Sprintf("Failed to push checkpoint images to %s: %v", backup.Spec.Registry.URL, err))
+		return
+	}
+
+	// The run was cancelled or replaced while the kubelet was checkpointing
+	if ctx.Err() != nil {
+		log.Info("Checkpoint run was cancelled, not recording its result", "pod", pod.Name)
+		return
+	}
+
+	// Record the checkpoint on the CheckpointBackup status
+	checkpointTime := metav1.Now()
+	err = r.updateRunStatus(ctx, key, func(backup *migrationv1.CheckpointBackup) {
+		backup.Status.Phase = migrationv1.CheckpointBackupSucceeded
+		backup.Status.NodeName = pod.Spec.NodeName
+		backup.Status.LastSuccessfulTime = &checkpointTime
+		backup.Status.Containers = checkpoints
+		recordCheckpointHistory(backup, migrationv1.CheckpointRecord{Time: checkpointTime, Containers: checkpoints})
+		setCheckpointBackupCondition(backup, migrationv1.CheckpointBackupConditionReady, metav1.ConditionTrue, ReasonCheckpointSucceeded,
+			fmt.Sprintf("Checkpointed %d container(s) of pod %s", len(checkpoints), pod.Name))
+	})
+	if err != nil {
+		log.Error(err, "Failed to update CheckpointBackup status")
+		return
+	}
+
+	r.Recorder.Eventf(&backup, corev1.EventTypeNormal, ReasonCheckpointSucceeded,
+		"Checkpointed %d container(s) of pod %s on node %s", len(checkpoints), pod.Name, pod.Spec.NodeName)
+	log.Info("Successfully checkpointed pod", "pod", pod.Name, "node", pod.Spec.NodeName, "containers", len(checkpoints))
+}
+
+// recordCheckpointHistory adds a successful checkpoint to the front of the status history and
+// drops the oldest checkpoints beyond the history limit
+func recordCheckpointHistory(backup *migrationv1.CheckpointBackup, record migrationv1.CheckpointRecord) {
+	limit := DefaultCheckpointHistoryLimit
+	if backup.Spec.HistoryLimit != nil && *backup.Spec.HistoryLimit > 0 {
+		limit = int(*backup.Spec.HistoryLimit)
+	}
+
+	backup.Status.History = append([]migrationv1.CheckpointRecord{record}, backup.Status.History...)
+	if len(backup.Status.History) > limit {
+		backup.Status.History = backup.Status.History[:limit]
+	}
+}
+
+// recordRunResult records an unsuccessful checkpoint run on the CheckpointBackup status and as a Warning event.
+// Cancelled runs are not recorded since a newer run has replaced them or the backup is gone.
+func (r *CheckpointBackupReconciler) recordRunResult(ctx context.Context, backup *migrationv1.CheckpointBackup, phase migrationv1.CheckpointBackupPhase, reason, message string) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	r.Recorder.Event(backup, corev1.EventTypeWarning, reason, message)
+	if err := r.updateRunStatus(ctx, client.ObjectKeyFromObject(backup), func(backup *migrationv1.CheckpointBackup) {
+		backup.Status.Phase = phase
+		setCheckpointBackupCondition(backup, migrationv1.CheckpointBackupConditionReady, metav1.ConditionFalse, reason, message)
+	}); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update CheckpointBackup status")
+	}
+}
+
+// updateRunStatus applies mutate to the latest CheckpointBackup and writes its status, retrying on conflicts
+func (r *CheckpointBackupReconciler) updateRunStatus(ctx context.Context, key types.NamespacedName, mutate func(backup *migrationv1.CheckpointBackup)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var backup migrationv1.CheckpointBackup
+		if err := r.Get(ctx, key, &backup); err != nil {
+			return err
+		}
+		mutate(&backup)
+		return r.Status().Update(ctx, &backup)
+	})
+}
+
+// updateStatusIfChanged writes the status of the CheckpointBackup if it differs from originalStatus
+func (r *CheckpointBackupReconciler) updateStatusIfChanged(ctx context.Context, backup *migrationv1.CheckpointBackup, originalStatus *migrationv1.CheckpointBackupStatus) error {
+	if equality.Semantic.DeepEqual(&backup.Status, originalStatus) {
+		return nil
+	}
+
+	if err := r.Status().Update(ctx, backup); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update CheckpointBackup status")
+		return err
+	}
+	return nil
+}
+
+// setCheckpointBackupCondition sets a condition on the CheckpointBackup status for its current generation
+func setCheckpointBackupCondition(backup *migrationv1.CheckpointBackup, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&backup.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: backup.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// clock returns the current time
+func (r *CheckpointBackupReconciler) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// getTargetPod gets the pod referenced by the CheckpointBackup
+func (r *CheckpointBackupReconciler) getTargetPod(ctx context.Context, backup *migrationv1.CheckpointBackup) (*corev1.Pod, error) {
+	namespace := backup.Spec.PodRef.Namespace
+	if namespace == "" {
+		namespace = backup.Namespace
+	}
+
+	var pod corev1.Pod
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      backup.Spec.PodRef.Name,
+		Namespace: namespace,
+	}, &pod); err != nil {
+		return nil, err
+	}
+
+	return &pod, nil
+}
+
+// checkpointPod checkpoints the containers listed in the CheckpointBackup with the Checkpoint strategy,
+// or every container of the pod if none are listed
+func (r *CheckpointBackupReconciler) checkpointPod(ctx context.Context, backup *migrationv1.CheckpointBackup, pod *corev1.Pod) ([]migrationv1.ContainerCheckpoint, error) {
+	if r.KubeletClient == nil {
+		return nil, fmt.Errorf("kubelet client not initialized")
+	}
+
+	timeout := r.CheckpointTimeout
+	if timeout == 0 {
+		timeout = DefaultCheckpointTimeout
+	}
+
+	containerNames := make([]string, 0, len(backup.Spec.Containers))
+	for _, container := range backup.Spec.Containers {
+		if container.Strategy == "" || container.Strategy == migrationv1.ContainerStrategyCheckpoint {
+			containerNames = append(containerNames, container.Name)
+		}
+	}
+	if len(backup.Spec.Containers) == 0 {
+		for _, container := range pod.Spec.Containers {
+			containerNames = append(containerNames, container.Name)
+		}
+	}
+	if len(containerNames) == 0 {
+		return nil, fmt.Errorf("no container of pod %s uses the Checkpoint strategy", pod.Name)
+	}
+
+	var checkpoints []migrationv1.ContainerCheckpoint
+	for _, containerName := range containerNames {
+		archives, err := r.KubeletClient.CheckpointContainer(ctx, pod.Spec.NodeName, pod.Namespace, pod.Name, containerName, timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		checkpointTime := metav1.Now()
+		checkpoints = append(checkpoints, migrationv1.ContainerCheckpoint{
+			Name:           containerName,
+			ArchivePath:    archives[0],
+			CheckpointTime: &checkpointTime,
+		})
+	}
+
+	return checkpoints, nil
+}
+
+// pushCheckpoints pushes the checkpoint archive of every container to the registry and records the pushed images
+func (r *CheckpointBackupReconciler) pushCheckpoints(ctx context.Context, backup *migrationv1.CheckpointBackup, pod *corev1.Pod, checkpoints []migrationv1.ContainerCheckpoint) error {
+	if r.RegistryClient == nil {
+		return fmt.Errorf("registry client not initialized")
+	}
+
+	credentials, err := r.getRegistryCredentials(ctx, backup)
+	if err != nil {
+		return err
+	}
+
+	// Checkpoint images must match the platform of the node they were taken on
+	architecture, operatingSystem := goruntime.GOARCH, "linux"
+	var node corev1.Node
+	if err := r.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, &node); err == nil {
+		architecture = node.Status.NodeInfo.Architecture
+		operatingSystem = node.Status.NodeInfo.OperatingSystem
+	}
+
+	for i := range checkpoints {
+		checkpoint := &checkpoints[i]
+
+		archivePath := checkpoint.ArchivePath
+		if r.CheckpointDir != "" {
+			archivePath = filepath.Join(r.CheckpointDir, filepath.Base(checkpoint.ArchivePath))
+		}
+
+		tag := checkpointImageTag(pod.Name, checkpoint.Name, checkpoint.CheckpointTime.Time)
+		pushed, err := r.RegistryClient.PushCheckpointImage(ctx, CheckpointImage{
+			Reference:     ImageReference(backup.Spec.Registry.URL, backup.Spec.Registry.Repository, tag),
+			ContainerName: checkpoint.Name,
+			ArchivePath:   archivePath,
+			Architecture:  architecture,
+			OS:            operatingSystem,
+			Created:       checkpoint.CheckpointTime.Time,
+		}, credentials)
+		if err != nil {
+			return fmt.Errorf("failed to push checkpoint of container %s: %w", checkpoint.Name, err)
+		}
+
+		checkpoint.Image = pushed.Reference
+		checkpoint.Digest = pushed.Digest
+		checkpoint.Size = pushed.Size
+	}
+
+	return nil
+}
+
+// getRegistryCredentials reads the registry credentials from the Secret referenced by the CheckpointBackup
+func (r *CheckpointBackupReconciler) getRegistryCredentials(ctx context.Context, backup *migrationv1.CheckpointBackup) (*RegistryCredentials, error) {
+	if backup.Spec.Registry.SecretRef == nil {
+		return nil, nil
+	}
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      backup.Spec.Registry.SecretRef.Name,
+		Namespace: backup.Namespace,
+	}, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get registry secret %s: %w", backup.Spec.Registry.SecretRef.Name, err)
+	}
+
+	return CredentialsFromSecret(&secret, backup.Spec.Registry.URL)
+}
+
+// findBackupsForPod maps a pod to the CheckpointBackups that reference it
+func (r *CheckpointBackupReconciler) findBackupsForPod(ctx context.Context, obj client.Object) []reconcile.Request {
+	var backupList migrationv1.CheckpointBackupList
+	if err := r.List(ctx, &backupList); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, backup := range backupList.Items {
+		namespace := backup.Spec.PodRef.Namespace
+		if namespace == "" {
+			namespace = backup.Namespace
+		}
+		if backup.Spec.PodRef.Name == obj.GetName() && namespace == obj.GetNamespace() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: backup.Name, Namespace: backup.Namespace},
+			})
+		}
+	}
+
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *CheckpointBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.runs == nil {
+		r.runs = newCheckpointRunTracker()
+	}
+
+	// Cancel the checkpoints still running in the background when the manager stops
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		r.runs.cancelAll()
+		return nil
+	})); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&migrationv1.CheckpointBackup{}).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.findBackupsForPod)).
 		Named("checkpointbackup").
 		Complete(r)
 }
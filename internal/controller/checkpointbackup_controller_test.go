@@ -18,11 +18,15 @@ package controller
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -51,7 +55,23 @@ var _ = Describe("CheckpointBackup Controller", func() {
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: migrationv1.CheckpointBackupSpec{
+						Schedule: "*/5 * * * *",
+						PodRef: migrationv1.PodRef{
+							Namespace: "default",
+							Name:      "test-pod",
+						},
+						ResourceRef: migrationv1.ResourceRef{
+							APIVersion: "v1",
+							Kind:       "Pod",
+							Namespace:  "default",
+							Name:       "test-pod",
+						},
+						Registry: migrationv1.Registry{
+							URL:        "registry.example.com",
+							Repository: "checkpoints",
+						},
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
@@ -66,19 +86,154 @@ var _ = Describe("CheckpointBackup Controller", func() {
 			By("Cleanup the specific resource instance CheckpointBackup")
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
 		})
-		It("should successfully reconcile the resource", func() {
+		It("should schedule the next checkpoint", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &CheckpointBackupReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 5*time.Minute))
+
+			By("Checking that the next schedule time has been recorded")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, checkpointbackup)).To(Succeed())
+			Expect(checkpointbackup.Status.NextScheduleTime).NotTo(BeNil())
+			Expect(checkpointbackup.Status.LastScheduleTime).To(BeNil())
+			Expect(checkpointbackup.Status.LastSuccessfulTime).To(BeNil())
+
+			By("Checking the phase and conditions")
+			Expect(checkpointbackup.Status.Phase).To(Equal(migrationv1.CheckpointBackupScheduled))
+			Expect(checkpointbackup.Status.ObservedGeneration).To(Equal(checkpointbackup.Generation))
+			Expect(meta.IsStatusConditionTrue(checkpointbackup.Status.Conditions, migrationv1.CheckpointBackupConditionScheduled)).To(BeTrue())
+		})
+
+		It("should report an invalid schedule as failed", func() {
+			By("Setting an invalid schedule")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, checkpointbackup)).To(Succeed())
+			checkpointbackup.Spec.Schedule = "not a schedule"
+			Expect(k8sClient.Update(ctx, checkpointbackup)).To(Succeed())
+
+			controllerReconciler := &CheckpointBackupReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
 			}
 
-			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
 			})
 			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+			Expect(result.RequeueAfter).To(BeZero())
+
+			By("Checking the phase and conditions")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, checkpointbackup)).To(Succeed())
+			Expect(checkpointbackup.Status.Phase).To(Equal(migrationv1.CheckpointBackupFailed))
+			Expect(checkpointbackup.Status.NextScheduleTime).To(BeNil())
+			condition := meta.FindStatusCondition(checkpointbackup.Status.Conditions, migrationv1.CheckpointBackupConditionScheduled)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(ReasonInvalidSchedule))
+		})
+	})
+
+	Context("When tagging checkpoint images", func() {
+		checkpointTime := time.Date(2025, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+		It("should tag images with the pod, container and checkpoint time", func() {
+			Expect(checkpointImageTag("web-0", "app", checkpointTime)).To(Equal("web-0-app-20250101100000"))
+		})
+
+		It("should keep tags of maximum length names within the registry limit", func() {
+			podName := strings.Repeat("p", 63)
+			tag := checkpointImageTag(podName, strings.Repeat("c", 63), checkpointTime)
+			Expect(tag).To(HaveLen(maxImageTagLength))
+			Expect(tag).To(MatchRegexp(`^p+-c+-[0-9a-f]{8}-20250101100000$`))
+
+			// Names sharing the truncated prefix still get distinct tags
+			Expect(checkpointImageTag(podName, strings.Repeat("c", 62)+"d", checkpointTime)).NotTo(Equal(tag))
+		})
+	})
+
+	Context("When a checkpoint is requested", func() {
+		now := time.Date(2025, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+		newBackup := func(request string, lastScheduleTime *time.Time) *migrationv1.CheckpointBackup {
+			backup := &migrationv1.CheckpointBackup{}
+			if request != "" {
+				backup.Annotations = map[string]string{CheckpointRequestAnnotation: request}
+			}
+			if lastScheduleTime != nil {
+				backup.Status.LastScheduleTime = &metav1.Time{Time: *lastScheduleTime}
+			}
+			return backup
+		}
+
+		It("should return the time of a pending request", func() {
+			earlier := now.Add(-time.Hour)
+			requested, untilRequested := checkpointRequestTime(newBackup("2025-01-01T09:30:00Z", &earlier), now)
+			Expect(requested).To(Equal(time.Date(2025, time.January, 1, 9, 30, 0, 0, time.UTC)))
+			Expect(untilRequested).To(BeNumerically("<=", 0))
+			requested, _ = checkpointRequestTime(newBackup("2025-01-01T09:30:00Z", nil), now)
+			Expect(requested).NotTo(BeZero())
+		})
+
+		It("should serve requests stamped slightly ahead of the local clock", func() {
+			requested, untilRequested := checkpointRequestTime(newBackup("2025-01-01T10:00:20Z", nil), now)
+			Expect(requested).To(Equal(time.Date(2025, time.January, 1, 10, 0, 20, 0, time.UTC)))
+			Expect(untilRequested).To(BeNumerically("<=", 0))
+		})
+
+		It("should wait for requests further ahead of the local clock", func() {
+			requested, untilRequested := checkpointRequestTime(newBackup("2025-01-01T11:00:00Z", nil), now)
+			Expect(requested).To(Equal(time.Date(2025, time.January, 1, 11, 0, 0, 0, time.UTC)))
+			Expect(untilRequested).To(Equal(time.Hour - checkpointRequestClockSkew))
+		})
+
+		It("should ignore served and invalid requests", func() {
+			served := time.Date(2025, time.January, 1, 9, 30, 0, 0, time.UTC)
+			for _, backup := range []*migrationv1.CheckpointBackup{
+				newBackup("2025-01-01T09:30:00Z", &served),
+				newBackup("yesterday", nil),
+				newBackup("", nil),
+			} {
+				requested, untilRequested := checkpointRequestTime(backup, now)
+				Expect(requested).To(BeZero())
+				Expect(untilRequested).To(BeZero())
+			}
+		})
+	})
+
+	Context("When a checkpoint succeeds", func() {
+		record := func(hour int) migrationv1.CheckpointRecord {
+			return migrationv1.CheckpointRecord{Time: metav1.NewTime(time.Date(2025, time.January, 1, hour, 0, 0, 0, time.UTC))}
+		}
+
+		It("should keep the newest checkpoints up to the history limit", func() {
+			limit := int32(2)
+			backup := &migrationv1.CheckpointBackup{}
+			backup.Spec.HistoryLimit = &limit
+
+			recordCheckpointHistory(backup, record(9))
+			recordCheckpointHistory(backup, record(10))
+			recordCheckpointHistory(backup, record(11))
+
+			Expect(backup.Status.History).To(HaveLen(2))
+			Expect(backup.Status.History[0].Time.Hour()).To(Equal(11))
+			Expect(backup.Status.History[1].Time.Hour()).To(Equal(10))
+		})
+
+		It("should default the history limit", func() {
+			backup := &migrationv1.CheckpointBackup{}
+			for hour := 0; hour < DefaultCheckpointHistoryLimit+2; hour++ {
+				recordCheckpointHistory(backup, record(hour))
+			}
+			Expect(backup.Status.History).To(HaveLen(DefaultCheckpointHistoryLimit))
 		})
 	})
 })
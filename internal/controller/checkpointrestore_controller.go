@@ -0,0 +1,658 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+const (
+	// CheckpointRestoreAnnotation is set on restored pods to the name of the CheckpointRestore that created them
+	CheckpointRestoreAnnotation = "migration.dcnlab.com/checkpoint-restore"
+
+	// restorePendingRequeueInterval is how often a restore waiting for its checkpoint images is retried
+	restorePendingRequeueInterval = 30 * time.Second
+)
+
+// Reasons used in CheckpointRestore conditions
+const (
+	ReasonBackupNotFound = "BackupNotFound"
+	ReasonNoCheckpoint   = "NoCheckpoint"
+	ReasonPodConflict    = "PodConflict"
+	ReasonPodRestoring   = "PodRestoring"
+	ReasonPodRunning     = "PodRunning"
+	ReasonPodFailed      = "PodFailed"
+
+	ReasonInvalidRestorePoint  = "InvalidRestorePoint"
+	ReasonRestorePointNotFound = "RestorePointNotFound"
+
+	// ReasonPodCreated is only used in events
+	ReasonPodCreated = "PodCreated"
+)
+
+// CheckpointRestoreReconciler reconciles a CheckpointRestore object
+type CheckpointRestoreReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=checkpointrestores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=checkpointrestores/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=checkpointrestores/finalizers,verbs=update
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=checkpointbackups,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;create
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// It creates the pod named by the CheckpointRestore from the checkpoint images of the
+// referenced CheckpointBackup, so that the container runtime restores the process state,
+// and tracks the pod until it is running.
+func (r *CheckpointRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the CheckpointRestore instance
+	var restore migrationv1.CheckpointRestore
+	if err := r.Get(ctx, req.NamespacedName, &restore); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("CheckpointRestore resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get CheckpointRestore")
+		return ctrl.Result{}, err
+	}
+
+	if restore.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+
+	// Status changes are collected on the fetched object and written once through the status subresource
+	originalStatus := restore.Status.DeepCopy()
+	restore.Status.ObservedGeneration = restore.Generation
+
+	result, err := r.reconcileRestore(ctx, &restore)
+	r.recordReadyEvent(&restore, originalStatus)
+	if updateErr := r.updateStatusIfChanged(ctx, &restore, originalStatus); updateErr != nil && err == nil {
+		return ctrl.Result{}, updateErr
+	}
+	return result, err
+}
+
+// reconcileRestore creates the restored pod if needed and records its progress on the CheckpointRestore status
+func (r *CheckpointRestoreReconciler) reconcileRestore(ctx context.Context, restore *migrationv1.CheckpointRestore) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// The restored pod drives the status once it exists
+	var pod corev1.Pod
+	err := r.Get(ctx, types.NamespacedName{Name: restore.Spec.PodName, Namespace: restore.Namespace}, &pod)
+	if err == nil {
+		if !isOwnedBy(&pod, restore) {
+			restore.Status.Phase = migrationv1.CheckpointRestoreFailed
+			setCheckpointRestoreCondition(restore, metav1.ConditionFalse, ReasonPodConflict,
+				fmt.Sprintf("Pod %s already exists and is not managed by this CheckpointRestore", pod.Name))
+			return ctrl.Result{}, nil
+		}
+		r.observePod(restore, &pod)
+		return ctrl.Result{}, nil
+	}
+	if !errors.IsNotFound(err) {
+		log.Error(err, "Failed to get restored pod", "pod", restore.Spec.PodName)
+		return ctrl.Result{}, err
+	}
+
+	// A restore that already produced a pod does not recreate it once it is gone
+	if restore.Status.PodRef != nil {
+		restore.Status.Phase = migrationv1.CheckpointRestoreFailed
+		setCheckpointRestoreCondition(restore, metav1.ConditionFalse, ReasonPodFailed,
+			fmt.Sprintf("Restored pod %s was deleted", restore.Spec.PodName))
+		return ctrl.Result{}, nil
+	}
+
+	// Resolve the checkpoint images to restore from
+	var backup *migrationv1.CheckpointBackup
+	var fetched migrationv1.CheckpointBackup
+	if err := r.Get(ctx, types.NamespacedName{Name: restore.Spec.BackupRef.Name, Namespace: restore.Namespace}, &fetched); err == nil {
+		backup = &fetched
+	} else if !errors.IsNotFound(err) {
+		log.Error(err, "Failed to get CheckpointBackup", "backup", restore.Spec.BackupRef.Name)
+		return ctrl.Result{}, err
+	}
+
+	images, record, reason, message := restoreImages(restore, backup)
+	if len(images) == 0 {
+		// A restore point that is invalid or missing from the history will not show up later
+		if reason == ReasonInvalidRestorePoint || reason == ReasonRestorePointNotFound {
+			log.Info("Restore point cannot be restored", "backup", restore.Spec.BackupRef.Name, "reason", reason)
+			restore.Status.Phase = migrationv1.CheckpointRestoreFailed
+			setCheckpointRestoreCondition(restore, metav1.ConditionFalse, reason, message)
+			return ctrl.Result{}, nil
+		}
+		log.Info("Checkpoint images are not available yet", "backup", restore.Spec.BackupRef.Name, "reason", reason)
+		restore.Status.Phase = migrationv1.CheckpointRestorePending
+		setCheckpointRestoreCondition(restore, metav1.ConditionFalse, reason, message)
+		return ctrl.Result{RequeueAfter: restorePendingRequeueInterval}, nil
+	}
+
+	// Build the pod from the workload's template, falling back to the checkpoint images alone
+	template, err := r.getPodTemplate(ctx, restore, backup)
+	if err != nil {
+		log.Error(err, "Failed to get pod template for restore")
+		return ctrl.Result{}, err
+	}
+
+	restoredPod, err := r.buildRestoredPod(ctx, restore, backup, template, images)
+	if err != nil {
+		log.Error(err, "Failed to build restored pod")
+		return ctrl.Result{}, err
+	}
+
+	// The claims of a StatefulSet ordinal must exist before its pod is scheduled
+	if identity := restore.Spec.StatefulSet; identity != nil {
+		var labels map[string]string
+		if template != nil {
+			labels = template.Labels
+		}
+		if err := ensureStatefulSetClaims(ctx, r.Client, restore.Namespace, labels, identity); err != nil {
+			log.Error(err, "Failed to create PersistentVolumeClaims for restored pod", "statefulSet", identity.Name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.Create(ctx, restoredPod); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		log.Error(err, "Failed to create restored pod", "pod", restoredPod.Name)
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Created restored pod", "pod", restoredPod.Name, "containers", len(images))
+	r.Recorder.Eventf(restore, corev1.EventTypeNormal, ReasonPodCreated,
+		"Created pod %s from the checkpoint images of CheckpointBackup %s", restoredPod.Name, restore.Spec.BackupRef.Name)
+	restore.Status.PodRef = &migrationv1.PodRef{Namespace: restoredPod.Namespace, Name: restoredPod.Name}
+	restore.Status.Containers = images
+	if record != nil {
+		restore.Status.RestorePoint = &migrationv1.RestorePointStatus{
+			Type:       migrationv1.RestorePointLatest,
+			Containers: record.Containers,
+		}
+		if !record.Time.IsZero() {
+			restore.Status.RestorePoint.CheckpointTime = record.Time.DeepCopy()
+		}
+		if restore.Spec.RestorePoint != nil && restore.Spec.RestorePoint.Type != "" {
+			restore.Status.RestorePoint.Type = restore.Spec.RestorePoint.Type
+		}
+	}
+	r.observePod(restore, restoredPod)
+	return ctrl.Result{}, nil
+}
+
+// observePod records the state of the restored pod on the CheckpointRestore status
+func (r *CheckpointRestoreReconciler) observePod(restore *migrationv1.CheckpointRestore, pod *corev1.Pod) {
+	restore.Status.PodRef = &migrationv1.PodRef{Namespace: pod.Namespace, Name: pod.Name}
+
+	switch pod.Status.Phase {
+	case corev1.PodRunning:
+		if restore.Status.RestoreTime == nil {
+			now := metav1.Now()
+			restore.Status.RestoreTime = &now
+		}
+		restore.Status.Phase = migrationv1.CheckpointRestoreRestored
+		setCheckpointRestoreCondition(restore, metav1.ConditionTrue, ReasonPodRunning,
+			fmt.Sprintf("Restored pod %s is running on node %s", pod.Name, pod.Spec.NodeName))
+	case corev1.PodFailed, corev1.PodSucceeded:
+		restore.Status.Phase = migrationv1.CheckpointRestoreFailed
+		setCheckpointRestoreCondition(restore, metav1.ConditionFalse, ReasonPodFailed,
+			fmt.Sprintf("Restored pod %s terminated (phase %s): %s", pod.Name, pod.Status.Phase, pod.Status.Message))
+	default:
+		restore.Status.Phase = migrationv1.CheckpointRestoreRestoring
+		setCheckpointRestoreCondition(restore, metav1.ConditionFalse, ReasonPodRestoring,
+			fmt.Sprintf("Waiting for restored pod %s to start running", pod.Name))
+	}
+}
+
+// restoreImages resolves the image each container is restored from. Images listed in the
+// CheckpointRestore take precedence over the checkpoint images of the selected restore point.
+// Containers that are recreated or skipped keep their strategy and are taken from the
+// CheckpointRestore, or from the CheckpointBackup if it lists no containers.
+// If no image can be resolved, the reason and message explain why.
+func restoreImages(restore *migrationv1.CheckpointRestore, backup *migrationv1.CheckpointBackup) ([]migrationv1.Container, *migrationv1.CheckpointRecord, string, string) {
+	var record *migrationv1.CheckpointRecord
+	reason, message := ReasonBackupNotFound, fmt.Sprintf("CheckpointBackup %s not found", restore.Spec.BackupRef.Name)
+	if backup != nil {
+		record, reason, message = selectRestorePoint(restore, backup)
+	}
+
+	checkpointImages := make(map[string]string)
+	var checkpointOrder []string
+	if record != nil {
+		for _, checkpoint := range record.Containers {
+			if checkpoint.Image == "" {
+				continue
+			}
+			checkpointImages[checkpoint.Name] = checkpoint.Image
+			checkpointOrder = append(checkpointOrder, checkpoint.Name)
+		}
+	}
+
+	var images []migrationv1.Container
+	if len(restore.Spec.Containers) > 0 {
+		for _, container := range restore.Spec.Containers {
+			if container.Strategy == migrationv1.ContainerStrategyRecreate || container.Strategy == migrationv1.ContainerStrategySkip {
+				images = append(images, container)
+				continue
+			}
+			image := container.Image
+			if image == "" {
+				if record == nil {
+					return nil, nil, reason, message
+				}
+				image = checkpointImages[container.Name]
+			}
+			if image == "" {
+				return nil, nil, ReasonRestorePointNotFound, fmt.Sprintf("No checkpoint image for container %s in the checkpoint taken at %s",
+					container.Name, record.Time.UTC().Format(time.RFC3339))
+			}
+			images = append(images, migrationv1.Container{Name: container.Name, Image: image})
+		}
+		return images, record, "", ""
+	}
+
+	if record == nil {
+		return nil, nil, reason, message
+	}
+	for _, name := range checkpointOrder {
+		images = append(images, migrationv1.Container{Name: name, Image: checkpointImages[name]})
+	}
+	if len(images) == 0 {
+		return nil, nil, ReasonNoCheckpoint, fmt.Sprintf("CheckpointBackup %s has no successful checkpoint yet", backup.Name)
+	}
+	for _, container := range backup.Spec.Containers {
+		if container.Strategy == migrationv1.ContainerStrategyRecreate || container.Strategy == migrationv1.ContainerStrategySkip {
+			images = append(images, container)
+		}
+	}
+	return images, record, "", ""
+}
+
+// selectRestorePoint returns the checkpoint of the backup selected by the restore point of the CheckpointRestore.
+// A checkpoint older than the latest one must have an image for every container of the latest one.
+// If no checkpoint is selected, the reason and message explain why.
+func selectRestorePoint(restore *migrationv1.CheckpointRestore, backup *migrationv1.CheckpointBackup) (*migrationv1.CheckpointRecord, string, string) {
+	history := backup.Status.History
+	if len(history) == 0 && len(backup.Status.Containers) > 0 {
+		// Backups taken before the history was recorded only know their latest checkpoint
+		latest := migrationv1.CheckpointRecord{Containers: backup.Status.Containers}
+		if backup.Status.LastSuccessfulTime != nil {
+			latest.Time = *backup.Status.LastSuccessfulTime
+		}
+		history = []migrationv1.CheckpointRecord{latest}
+	}
+	if len(history) == 0 {
+		return nil, ReasonNoCheckpoint, fmt.Sprintf("CheckpointBackup %s has no successful checkpoint yet", backup.Name)
+	}
+
+	point := migrationv1.RestorePoint{Type: migrationv1.RestorePointLatest}
+	if restore.Spec.RestorePoint != nil {
+		point = *restore.Spec.RestorePoint
+	}
+
+	var record *migrationv1.CheckpointRecord
+	switch point.Type {
+	case migrationv1.RestorePointLatest, "":
+		record = &history[0]
+	case migrationv1.RestorePointBeforeTime:
+		if point.Time == nil {
+			return nil, ReasonInvalidRestorePoint, "Restore point BeforeTime requires a time"
+		}
+		for i := range history {
+			if !history[i].Time.After(point.Time.Time) {
+				record = &history[i]
+				break
+			}
+		}
+		if record == nil {
+			return nil, ReasonRestorePointNotFound, fmt.Sprintf("CheckpointBackup %s has no checkpoint taken at or before %s",
+				backup.Name, point.Time.UTC().Format(time.RFC3339))
+		}
+	case migrationv1.RestorePointDigest:
+		if point.Digest == "" {
+			return nil, ReasonInvalidRestorePoint, "Restore point Digest requires a digest"
+		}
+		for i := range history {
+			for _, checkpoint := range history[i].Containers {
+				if checkpoint.Digest == point.Digest {
+					record = &history[i]
+					break
+				}
+			}
+			if record != nil {
+				break
+			}
+		}
+		if record == nil {
+			return nil, ReasonRestorePointNotFound, fmt.Sprintf("CheckpointBackup %s has no checkpoint image with digest %s", backup.Name, point.Digest)
+		}
+	default:
+		return nil, ReasonInvalidRestorePoint, fmt.Sprintf("Unknown restore point type %s", point.Type)
+	}
+
+	// Every container of the latest checkpoint must be restorable from the selected one
+	for _, latest := range history[0].Containers {
+		found := false
+		for _, checkpoint := range record.Containers {
+			if checkpoint.Name == latest.Name && checkpoint.Image != "" {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ReasonRestorePointNotFound, fmt.Sprintf("Checkpoint taken at %s has no image for container %s",
+				record.Time.UTC().Format(time.RFC3339), latest.Name)
+		}
+	}
+
+	return record, "", ""
+}
+
+// getPodTemplate returns the pod template to restore into: the template given in the CheckpointRestore,
+// the template of the workload referenced by the CheckpointBackup, or the spec of the original pod if it
+// is still around. It returns nil if none is available in this cluster.
+func (r *CheckpointRestoreReconciler) getPodTemplate(ctx context.Context, restore *migrationv1.CheckpointRestore, backup *migrationv1.CheckpointBackup) (*corev1.PodTemplateSpec, error) {
+	if restore.Spec.PodTemplate != nil {
+		return restore.Spec.PodTemplate.DeepCopy(), nil
+	}
+	if backup == nil {
+		return nil, nil
+	}
+
+	ref := backup.Spec.ResourceRef
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = restore.Namespace
+	}
+	key := types.NamespacedName{Name: ref.Name, Namespace: namespace}
+
+	switch ref.Kind {
+	case "Deployment":
+		var deployment appsv1.Deployment
+		if err := r.Get(ctx, key, &deployment); err == nil {
+			return deployment.Spec.Template.DeepCopy(), nil
+		} else if !errors.IsNotFound(err) {
+			return nil, err
+		}
+	case "StatefulSet":
+		var statefulSet appsv1.StatefulSet
+		if err := r.Get(ctx, key, &statefulSet); err == nil {
+			return statefulSet.Spec.Template.DeepCopy(), nil
+		} else if !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	// Fall back to the original pod
+	podNamespace := backup.Spec.PodRef.Namespace
+	if podNamespace == "" {
+		podNamespace = backup.Namespace
+	}
+	var pod corev1.Pod
+	if err := r.Get(ctx, types.NamespacedName{Name: backup.Spec.PodRef.Name, Namespace: podNamespace}, &pod); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      pod.Labels,
+			Annotations: pod.Annotations,
+		},
+		Spec: *sanitizePodSpec(pod.Spec.DeepCopy()),
+	}, nil
+}
+
+// sanitizePodSpec removes the fields of a running pod's spec that are set by the API server or scheduler
+func sanitizePodSpec(spec *corev1.PodSpec) *corev1.PodSpec {
+	spec.NodeName = ""
+
+	// The service account token volume is injected again when the pod is created
+	var volumes []corev1.Volume
+	injected := make(map[string]bool)
+	for _, volume := range spec.Volumes {
+		if strings.HasPrefix(volume.Name, "kube-api-access-") {
+			injected[volume.Name] = true
+			continue
+		}
+		volumes = append(volumes, volume)
+	}
+	spec.Volumes = volumes
+
+	removeMounts := func(containers []corev1.Container) {
+		for i := range containers {
+			var mounts []corev1.VolumeMount
+			for _, mount := range containers[i].VolumeMounts {
+				if !injected[mount.Name] {
+					mounts = append(mounts, mount)
+				}
+			}
+			containers[i].VolumeMounts = mounts
+		}
+	}
+	removeMounts(spec.InitContainers)
+	removeMounts(spec.Containers)
+
+	return spec
+}
+
+// buildRestoredPod builds the pod that restores the checkpointed containers from their checkpoint images
+func (r *CheckpointRestoreReconciler) buildRestoredPod(ctx context.Context, restore *migrationv1.CheckpointRestore, backup *migrationv1.CheckpointBackup, template *corev1.PodTemplateSpec, images []migrationv1.Container) (*corev1.Pod, error) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        restore.Spec.PodName,
+			Namespace:   restore.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+	}
+
+	if template != nil {
+		for key, value := range template.Labels {
+			pod.Labels[key] = value
+		}
+		for key, value := range template.Annotations {
+			pod.Annotations[key] = value
+		}
+		pod.Spec = *template.Spec.DeepCopy()
+	}
+	pod.Annotations[CheckpointRestoreAnnotation] = restore.Name
+
+	// Containers with a checkpoint image are restored from it, the others start from their own image.
+	// Skipped containers are left out; native sidecars are restored in place among the init containers.
+	for _, image := range images {
+		isNamed := func(container corev1.Container) bool { return container.Name == image.Name }
+		if image.Strategy == migrationv1.ContainerStrategySkip {
+			pod.Spec.InitContainers = slices.DeleteFunc(pod.Spec.InitContainers, isNamed)
+			pod.Spec.Containers = slices.DeleteFunc(pod.Spec.Containers, isNamed)
+			continue
+		}
+
+		index := slices.IndexFunc(pod.Spec.Containers, isNamed)
+		containers := pod.Spec.Containers
+		if index < 0 {
+			index = slices.IndexFunc(pod.Spec.InitContainers, isNamed)
+			containers = pod.Spec.InitContainers
+		}
+		switch {
+		case index < 0:
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+				Name:  image.Name,
+				Image: image.Image,
+			})
+		case image.Strategy != migrationv1.ContainerStrategyRecreate:
+			containers[index].Image = image.Image
+		}
+	}
+
+	// Let the kubelet pull the checkpoint images with the registry credentials when they are usable for pulls
+	if backup != nil && backup.Spec.Registry.SecretRef != nil {
+		var secret corev1.Secret
+		err := r.Get(ctx, types.NamespacedName{Name: backup.Spec.Registry.SecretRef.Name, Namespace: restore.Namespace}, &secret)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil && secret.Type == corev1.SecretTypeDockerConfigJson && !hasImagePullSecret(pod.Spec.ImagePullSecrets, secret.Name) {
+			pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: secret.Name})
+		}
+	}
+
+	// A StatefulSet pod takes the identity of its ordinal and is left for the StatefulSet to adopt as its controller
+	if restore.Spec.StatefulSet != nil {
+		applyStatefulSetIdentity(pod, restore.Spec.StatefulSet)
+		if err := controllerutil.SetOwnerReference(restore, pod, r.Scheme); err != nil {
+			return nil, err
+		}
+		return pod, nil
+	}
+
+	// A Deployment pod takes the pod-template-hash of its ReplicaSet and is likewise left for the ReplicaSet to adopt
+	if identity := restore.Spec.Deployment; identity != nil {
+		pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey] = identity.PodTemplateHash
+		if err := controllerutil.SetOwnerReference(restore, pod, r.Scheme); err != nil {
+			return nil, err
+		}
+		return pod, nil
+	}
+
+	if err := controllerutil.SetControllerReference(restore, pod, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	return pod, nil
+}
+
+// isOwnedBy reports whether the pod was created by the CheckpointRestore, whether or not it is still its controller
+func isOwnedBy(pod *corev1.Pod, restore *migrationv1.CheckpointRestore) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.UID == restore.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// hasImagePullSecret reports whether the pull secret is already referenced
+func hasImagePullSecret(secrets []corev1.LocalObjectReference, name string) bool {
+	for _, secret := range secrets {
+		if secret.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// updateStatusIfChanged writes the status of the CheckpointRestore if it differs from originalStatus
+func (r *CheckpointRestoreReconciler) updateStatusIfChanged(ctx context.Context, restore *migrationv1.CheckpointRestore, originalStatus *migrationv1.CheckpointRestoreStatus) error {
+	if equality.Semantic.DeepEqual(&restore.Status, originalStatus) {
+		return nil
+	}
+
+	if err := r.Status().Update(ctx, restore); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update CheckpointRestore status")
+		return err
+	}
+	return nil
+}
+
+// recordReadyEvent records an event when the reason of the Ready condition changes, so that the progress of
+// the restore shows up once per step. Failed restores are recorded as Warning events.
+func (r *CheckpointRestoreReconciler) recordReadyEvent(restore *migrationv1.CheckpointRestore, originalStatus *migrationv1.CheckpointRestoreStatus) {
+	condition := meta.FindStatusCondition(restore.Status.Conditions, migrationv1.CheckpointRestoreConditionReady)
+	if condition == nil {
+		return
+	}
+	if previous := meta.FindStatusCondition(originalStatus.Conditions, migrationv1.CheckpointRestoreConditionReady); previous != nil && previous.Reason == condition.Reason {
+		return
+	}
+
+	eventType := corev1.EventTypeNormal
+	if restore.Status.Phase == migrationv1.CheckpointRestoreFailed {
+		eventType = corev1.EventTypeWarning
+	}
+	r.Recorder.Event(restore, eventType, condition.Reason, condition.Message)
+}
+
+// setCheckpointRestoreCondition sets the Ready condition on the CheckpointRestore status for its current generation
+func setCheckpointRestoreCondition(restore *migrationv1.CheckpointRestore, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+		Type:               migrationv1.CheckpointRestoreConditionReady,
+		Status:             status,
+		ObservedGeneration: restore.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// findRestoresForBackup maps a CheckpointBackup to the pending CheckpointRestores that restore from it
+func (r *CheckpointRestoreReconciler) findRestoresForBackup(ctx context.Context, obj client.Object) []ctrl.Request {
+	var restoreList migrationv1.CheckpointRestoreList
+	if err := r.List(ctx, &restoreList, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []ctrl.Request
+	for _, restore := range restoreList.Items {
+		if restore.Spec.BackupRef.Name == obj.GetName() && restore.Status.PodRef == nil {
+			requests = append(requests, ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: restore.Name, Namespace: restore.Namespace},
+			})
+		}
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CheckpointRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&migrationv1.CheckpointRestore{}).
+		// Restored StatefulSet and Deployment pods are not controlled by their CheckpointRestore, so Owns would miss them
+		Watches(&corev1.Pod{}, handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &migrationv1.CheckpointRestore{})).
+		Watches(&migrationv1.CheckpointBackup{}, handler.EnqueueRequestsFromMapFunc(r.findRestoresForBackup)).
+		Named("checkpointrestore").
+		Complete(r)
+}
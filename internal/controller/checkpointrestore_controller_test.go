@@ -0,0 +1,544 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+var _ = Describe("CheckpointRestore Controller", func() {
+	Context("When reconciling a resource", func() {
+		const (
+			resourceName = "test-restore"
+			backupName   = "test-restore-backup"
+			podName      = "test-restored-pod"
+		)
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		backupNamespacedName := types.NamespacedName{
+			Name:      backupName,
+			Namespace: "default",
+		}
+		podNamespacedName := types.NamespacedName{
+			Name:      podName,
+			Namespace: "default",
+		}
+		checkpointrestore := &migrationv1.CheckpointRestore{}
+
+		BeforeEach(func() {
+			By("creating the CheckpointBackup to restore from")
+			backup := &migrationv1.CheckpointBackup{}
+			err := k8sClient.Get(ctx, backupNamespacedName, backup)
+			if err != nil && errors.IsNotFound(err) {
+				backup = &migrationv1.CheckpointBackup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      backupName,
+						Namespace: "default",
+					},
+					Spec: migrationv1.CheckpointBackupSpec{
+						Schedule: "*/5 * * * *",
+						PodRef: migrationv1.PodRef{
+							Namespace: "default",
+							Name:      "test-original-pod",
+						},
+						ResourceRef: migrationv1.ResourceRef{
+							APIVersion: "v1",
+							Kind:       "Pod",
+							Namespace:  "default",
+							Name:       "test-original-pod",
+						},
+						Registry: migrationv1.Registry{
+							URL:        "registry.example.com",
+							Repository: "checkpoints",
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, backup)).To(Succeed())
+			}
+
+			By("creating the custom resource for the Kind CheckpointRestore")
+			err = k8sClient.Get(ctx, typeNamespacedName, checkpointrestore)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &migrationv1.CheckpointRestore{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: migrationv1.CheckpointRestoreSpec{
+						BackupRef: migrationv1.BackupRef{Name: backupName},
+						PodName:   podName,
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &migrationv1.CheckpointRestore{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance CheckpointRestore")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			backup := &migrationv1.CheckpointBackup{}
+			Expect(k8sClient.Get(ctx, backupNamespacedName, backup)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, backup)).To(Succeed())
+
+			pod := &corev1.Pod{}
+			if err := k8sClient.Get(ctx, podNamespacedName, pod); err == nil {
+				Expect(k8sClient.Delete(ctx, pod)).To(Succeed())
+			}
+		})
+
+		It("should wait for a successful checkpoint", func() {
+			controllerReconciler := &CheckpointRestoreReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(restorePendingRequeueInterval))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, checkpointrestore)).To(Succeed())
+			Expect(checkpointrestore.Status.Phase).To(Equal(migrationv1.CheckpointRestorePending))
+			condition := meta.FindStatusCondition(checkpointrestore.Status.Conditions, migrationv1.CheckpointRestoreConditionReady)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(ReasonNoCheckpoint))
+
+			err = k8sClient.Get(ctx, podNamespacedName, &corev1.Pod{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should restore the pod from the last checkpoint images", func() {
+			By("Recording a successful checkpoint on the backup")
+			backup := &migrationv1.CheckpointBackup{}
+			Expect(k8sClient.Get(ctx, backupNamespacedName, backup)).To(Succeed())
+			backup.Status.Phase = migrationv1.CheckpointBackupSucceeded
+			backup.Status.Containers = []migrationv1.ContainerCheckpoint{{
+				Name:  "app",
+				Image: "registry.example.com/checkpoints:test-original-pod-app-20250101100000",
+			}}
+			Expect(k8sClient.Status().Update(ctx, backup)).To(Succeed())
+
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &CheckpointRestoreReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(Equal(fmt.Sprintf("Normal %s Created pod %s from the checkpoint images of CheckpointBackup %s", ReasonPodCreated, podName, backupName))))
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal " + ReasonPodRestoring)))
+
+			By("Checking the restored pod")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, podNamespacedName, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(1))
+			Expect(pod.Spec.Containers[0].Image).To(Equal("registry.example.com/checkpoints:test-original-pod-app-20250101100000"))
+			Expect(pod.Annotations).To(HaveKeyWithValue(CheckpointRestoreAnnotation, resourceName))
+			Expect(pod.OwnerReferences).To(HaveLen(1))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, checkpointrestore)).To(Succeed())
+			Expect(checkpointrestore.Status.Phase).To(Equal(migrationv1.CheckpointRestoreRestoring))
+			Expect(checkpointrestore.Status.PodRef).NotTo(BeNil())
+			Expect(checkpointrestore.Status.PodRef.Name).To(Equal(podName))
+
+			By("Marking the restored pod as running")
+			pod.Status.Phase = corev1.PodRunning
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, checkpointrestore)).To(Succeed())
+			Expect(checkpointrestore.Status.Phase).To(Equal(migrationv1.CheckpointRestoreRestored))
+			Expect(checkpointrestore.Status.RestoreTime).NotTo(BeNil())
+			Expect(meta.IsStatusConditionTrue(checkpointrestore.Status.Conditions, migrationv1.CheckpointRestoreConditionReady)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal " + ReasonPodRunning)))
+
+			By("Reconciling again without recording the same step twice")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+		It("should restore from the newest checkpoint before the restore point time", func() {
+			older := metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+			newer := metav1.NewTime(time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC))
+
+			By("Recording two checkpoints in the backup history")
+			backup := &migrationv1.CheckpointBackup{}
+			Expect(k8sClient.Get(ctx, backupNamespacedName, backup)).To(Succeed())
+			backup.Status.Phase = migrationv1.CheckpointBackupSucceeded
+			backup.Status.LastSuccessfulTime = &newer
+			backup.Status.Containers = []migrationv1.ContainerCheckpoint{{
+				Name:  "app",
+				Image: "registry.example.com/checkpoints:test-original-pod-app-20250101110000",
+			}}
+			backup.Status.History = []migrationv1.CheckpointRecord{
+				{Time: newer, Containers: backup.Status.Containers},
+				{Time: older, Containers: []migrationv1.ContainerCheckpoint{{
+					Name:   "app",
+					Image:  "registry.example.com/checkpoints:test-original-pod-app-20250101100000",
+					Digest: "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				}}},
+			}
+			Expect(k8sClient.Status().Update(ctx, backup)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, checkpointrestore)).To(Succeed())
+			checkpointrestore.Spec.RestorePoint = &migrationv1.RestorePoint{
+				Type: migrationv1.RestorePointBeforeTime,
+				Time: &metav1.Time{Time: older.Add(30 * time.Minute)},
+			}
+			Expect(k8sClient.Update(ctx, checkpointrestore)).To(Succeed())
+
+			controllerReconciler := &CheckpointRestoreReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, podNamespacedName, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Image).To(Equal("registry.example.com/checkpoints:test-original-pod-app-20250101100000"))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, checkpointrestore)).To(Succeed())
+			Expect(checkpointrestore.Status.RestorePoint).NotTo(BeNil())
+			Expect(checkpointrestore.Status.RestorePoint.Type).To(Equal(migrationv1.RestorePointBeforeTime))
+			Expect(checkpointrestore.Status.RestorePoint.CheckpointTime.Equal(&older)).To(BeTrue())
+		})
+
+		It("should fail when the restore point digest is not in the history", func() {
+			checkpointTime := metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+
+			backup := &migrationv1.CheckpointBackup{}
+			Expect(k8sClient.Get(ctx, backupNamespacedName, backup)).To(Succeed())
+			backup.Status.Phase = migrationv1.CheckpointBackupSucceeded
+			backup.Status.LastSuccessfulTime = &checkpointTime
+			backup.Status.Containers = []migrationv1.ContainerCheckpoint{{
+				Name:   "app",
+				Image:  "registry.example.com/checkpoints:test-original-pod-app-20250101100000",
+				Digest: "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			}}
+			Expect(k8sClient.Status().Update(ctx, backup)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, checkpointrestore)).To(Succeed())
+			checkpointrestore.Spec.RestorePoint = &migrationv1.RestorePoint{
+				Type:   migrationv1.RestorePointDigest,
+				Digest: "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+			}
+			Expect(k8sClient.Update(ctx, checkpointrestore)).To(Succeed())
+
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &CheckpointRestoreReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning " + ReasonRestorePointNotFound)))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, checkpointrestore)).To(Succeed())
+			Expect(checkpointrestore.Status.Phase).To(Equal(migrationv1.CheckpointRestoreFailed))
+			condition := meta.FindStatusCondition(checkpointrestore.Status.Conditions, migrationv1.CheckpointRestoreConditionReady)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(ReasonRestorePointNotFound))
+
+			err = k8sClient.Get(ctx, podNamespacedName, &corev1.Pod{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("When restoring a StatefulSet ordinal", func() {
+		const (
+			resourceName = "web-1-restore"
+			backupName   = "web-1-backup"
+			podName      = "web-1"
+		)
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+		backupNamespacedName := types.NamespacedName{Name: backupName, Namespace: "default"}
+		podNamespacedName := types.NamespacedName{Name: podName, Namespace: "default"}
+		claimNamespacedName := types.NamespacedName{Name: "data-web-1", Namespace: "default"}
+
+		BeforeEach(func() {
+			By("creating the CheckpointBackup of the ordinal with a successful checkpoint")
+			backup := &migrationv1.CheckpointBackup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      backupName,
+					Namespace: "default",
+				},
+				Spec: migrationv1.CheckpointBackupSpec{
+					Schedule: "*/5 * * * *",
+					PodRef:   migrationv1.PodRef{Namespace: "default", Name: podName},
+					ResourceRef: migrationv1.ResourceRef{
+						APIVersion: "apps/v1",
+						Kind:       "StatefulSet",
+						Namespace:  "default",
+						Name:       "web",
+					},
+					Registry: migrationv1.Registry{
+						URL:        "registry.example.com",
+						Repository: "checkpoints",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, backup)).To(Succeed())
+			backup.Status.Phase = migrationv1.CheckpointBackupSucceeded
+			backup.Status.Containers = []migrationv1.ContainerCheckpoint{{
+				Name:  "app",
+				Image: "registry.example.com/checkpoints:web-1-app-20250101100000",
+			}}
+			Expect(k8sClient.Status().Update(ctx, backup)).To(Succeed())
+
+			By("creating the CheckpointRestore with the identity of the ordinal")
+			restore := &migrationv1.CheckpointRestore{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: migrationv1.CheckpointRestoreSpec{
+					BackupRef: migrationv1.BackupRef{Name: backupName},
+					PodName:   podName,
+					PodTemplate: &corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Name:         "app",
+								Image:        "nginx",
+								VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+							}},
+						},
+					},
+					StatefulSet: &migrationv1.StatefulSetIdentity{
+						Name:         "web",
+						Ordinal:      1,
+						ServiceName:  "web",
+						RevisionHash: "web-5d8f7c9b",
+						VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+							ObjectMeta: metav1.ObjectMeta{Name: "data"},
+							Spec: corev1.PersistentVolumeClaimSpec{
+								AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+								Resources: corev1.VolumeResourceRequirements{
+									Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+								},
+							},
+						}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, restore)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("Cleanup the CheckpointRestore, its backup, pod and claim")
+			restore := &migrationv1.CheckpointRestore{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, restore)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, restore)).To(Succeed())
+
+			backup := &migrationv1.CheckpointBackup{}
+			Expect(k8sClient.Get(ctx, backupNamespacedName, backup)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, backup)).To(Succeed())
+
+			pod := &corev1.Pod{}
+			if err := k8sClient.Get(ctx, podNamespacedName, pod); err == nil {
+				Expect(k8sClient.Delete(ctx, pod)).To(Succeed())
+			}
+			claim := &corev1.PersistentVolumeClaim{}
+			if err := k8sClient.Get(ctx, claimNamespacedName, claim); err == nil {
+				Expect(k8sClient.Delete(ctx, claim)).To(Succeed())
+			}
+		})
+
+		It("should restore the pod with the identity of the ordinal", func() {
+			controllerReconciler := &CheckpointRestoreReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the claim of the ordinal")
+			claim := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(ctx, claimNamespacedName, claim)).To(Succeed())
+			Expect(claim.Labels).To(HaveKeyWithValue("app", "web"))
+			Expect(claim.OwnerReferences).To(BeEmpty())
+
+			By("Checking the restored pod")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, podNamespacedName, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Image).To(Equal("registry.example.com/checkpoints:web-1-app-20250101100000"))
+			Expect(pod.Labels).To(HaveKeyWithValue("app", "web"))
+			Expect(pod.Labels).To(HaveKeyWithValue("statefulset.kubernetes.io/pod-name", podName))
+			Expect(pod.Labels).To(HaveKeyWithValue("apps.kubernetes.io/pod-index", "1"))
+			Expect(pod.Labels).To(HaveKeyWithValue("controller-revision-hash", "web-5d8f7c9b"))
+			Expect(pod.Spec.Hostname).To(Equal(podName))
+			Expect(pod.Spec.Subdomain).To(Equal("web"))
+			Expect(pod.Spec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-web-1"},
+				},
+			}))
+
+			By("Checking the StatefulSet can adopt the restored pod")
+			Expect(pod.OwnerReferences).To(HaveLen(1))
+			Expect(metav1.GetControllerOf(pod)).To(BeNil())
+
+			restore := &migrationv1.CheckpointRestore{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, restore)).To(Succeed())
+			Expect(restore.Status.Phase).To(Equal(migrationv1.CheckpointRestoreRestoring))
+		})
+	})
+
+	Context("When restoring a Deployment replica", func() {
+		ctx := context.Background()
+
+		It("should leave the restored pod for the ReplicaSet of its revision to adopt", func() {
+			restore := &migrationv1.CheckpointRestore{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-7c9d5-x2kqp-restore", Namespace: "default", UID: types.UID("restore-uid")},
+				Spec: migrationv1.CheckpointRestoreSpec{
+					BackupRef:  migrationv1.BackupRef{Name: "api-7c9d5-x2kqp-backup"},
+					PodName:    "api-7c9d5-x2kqp",
+					Deployment: &migrationv1.DeploymentIdentity{Name: "api", PodTemplateHash: "7c9d5"},
+				},
+			}
+			template := &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "api:1.0"}}},
+			}
+			images := []migrationv1.Container{{Name: "app", Image: "registry.example.com/checkpoints:api-app-20250101100000"}}
+
+			reconciler := &CheckpointRestoreReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			pod, err := reconciler.buildRestoredPod(ctx, restore, nil, template, images)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pod.Labels).To(HaveKeyWithValue("app", "api"))
+			Expect(pod.Labels).To(HaveKeyWithValue("pod-template-hash", "7c9d5"))
+			Expect(pod.OwnerReferences).To(HaveLen(1))
+			Expect(pod.OwnerReferences[0].UID).To(Equal(restore.UID))
+			Expect(metav1.GetControllerOf(pod)).To(BeNil())
+			Expect(isOwnedBy(pod, restore)).To(BeTrue())
+		})
+	})
+
+	Context("When restoring with container rules", func() {
+		ctx := context.Background()
+
+		It("should restore checkpointed containers, recreate others and leave skipped ones out", func() {
+			checkpointTime := metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+			backup := &migrationv1.CheckpointBackup{
+				ObjectMeta: metav1.ObjectMeta{Name: "rules-backup", Namespace: "default"},
+				Spec: migrationv1.CheckpointBackupSpec{
+					Containers: []migrationv1.Container{
+						{Name: "app", Image: "app:1.0", Strategy: migrationv1.ContainerStrategyCheckpoint},
+						{Name: "cache", Image: "redis:8", Strategy: migrationv1.ContainerStrategyCheckpoint},
+						{Name: "log-shipper", Image: "fluent-bit:4.0", Strategy: migrationv1.ContainerStrategyRecreate},
+						{Name: "istio-proxy", Image: "istio/proxyv2:1.26", Strategy: migrationv1.ContainerStrategySkip},
+					},
+				},
+				Status: migrationv1.CheckpointBackupStatus{
+					LastSuccessfulTime: &checkpointTime,
+					Containers: []migrationv1.ContainerCheckpoint{
+						{Name: "app", Image: "registry.example.com/checkpoints:rules-app-20250101100000"},
+						{Name: "cache", Image: "registry.example.com/checkpoints:rules-cache-20250101100000"},
+					},
+				},
+			}
+			restore := &migrationv1.CheckpointRestore{
+				ObjectMeta: metav1.ObjectMeta{Name: "rules-restore", Namespace: "default"},
+				Spec: migrationv1.CheckpointRestoreSpec{
+					BackupRef: migrationv1.BackupRef{Name: backup.Name},
+					PodName:   "rules-pod",
+				},
+			}
+			template := &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: "cache", Image: "redis:8", RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways)},
+						{Name: "log-shipper", Image: "fluent-bit:4.1", RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways)},
+					},
+					Containers: []corev1.Container{
+						{Name: "app", Image: "app:1.0"},
+						{Name: "istio-proxy", Image: "istio/proxyv2:1.26"},
+					},
+				},
+			}
+
+			images, _, reason, _ := restoreImages(restore, backup)
+			Expect(reason).To(BeEmpty())
+			Expect(images).To(HaveLen(4))
+
+			reconciler := &CheckpointRestoreReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			pod, err := reconciler.buildRestoredPod(ctx, restore, backup, template, images)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pod.Spec.InitContainers).To(HaveLen(2))
+			Expect(pod.Spec.InitContainers[0].Image).To(Equal("registry.example.com/checkpoints:rules-cache-20250101100000"))
+			Expect(pod.Spec.InitContainers[1].Image).To(Equal("fluent-bit:4.1"))
+			Expect(pod.Spec.Containers).To(HaveLen(1))
+			Expect(pod.Spec.Containers[0].Image).To(Equal("registry.example.com/checkpoints:rules-app-20250101100000"))
+		})
+	})
+})
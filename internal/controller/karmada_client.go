@@ -19,45 +19,31 @@ package controller
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 
+	configv1alpha1 "github.com/karmada-io/karmada/pkg/apis/config/v1alpha1"
 	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-const (
-	// KarmadaKubeconfigPath is the path where the Karmada kubeconfig is mounted
-	KarmadaKubeconfigPath = "/etc/karmada/kubeconfig"
-)
-
 // KarmadaClient wraps a client for Karmada operations
 type KarmadaClient struct {
 	client.Client
 	restClient rest.Interface
+	config     *rest.Config
 }
 
-// NewKarmadaClient creates a new client for Karmada operations using the mounted kubeconfig
-func NewKarmadaClient() (*KarmadaClient, error) {
+// NewKarmadaClient creates a new client for Karmada operations from the config of the Karmada API server
+func NewKarmadaClient(config *rest.Config) (*KarmadaClient, error) {
 	logger := log.Log.WithName("karmada-client")
 
-	// Check if Karmada kubeconfig exists
-	if _, err := os.Stat(KarmadaKubeconfigPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("Karmada kubeconfig not found at %s", KarmadaKubeconfigPath)
-	}
-
-	// Load Karmada kubeconfig
-	config, err := clientcmd.BuildConfigFromFlags("", KarmadaKubeconfigPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load Karmada kubeconfig: %w", err)
-	}
-
 	// Create scheme with Karmada types
 	karmadaScheme := runtime.NewScheme()
 	if err := scheme.AddToScheme(karmadaScheme); err != nil {
@@ -66,6 +52,9 @@ func NewKarmadaClient() (*KarmadaClient, error) {
 	if err := karmadav1alpha1.AddToScheme(karmadaScheme); err != nil {
 		return nil, fmt.Errorf("failed to add Karmada types to scheme: %w", err)
 	}
+	if err := configv1alpha1.AddToScheme(karmadaScheme); err != nil {
+		return nil, fmt.Errorf("failed to add Karmada config types to scheme: %w", err)
+	}
 
 	// Create Karmada client
 	karmadaClient, err := client.New(config, client.Options{
@@ -92,11 +81,12 @@ func NewKarmadaClient() (*KarmadaClient, error) {
 	return &KarmadaClient{
 		Client:     karmadaClient,
 		restClient: restClient,
+		config:     config,
 	}, nil
 }
 
-// CreateOrUpdatePropagationPolicy creates or updates a PropagationPolicy in Karmada
-func (k *KarmadaClient) CreateOrUpdatePropagationPolicy(ctx context.Context, policy *karmadav1alpha1.PropagationPolicy) error {
+// CreateOrUpdatePropagationPolicy creates or updates a PropagationPolicy in Karmada and reports which it did
+func (k *KarmadaClient) CreateOrUpdatePropagationPolicy(ctx context.Context, policy *karmadav1alpha1.PropagationPolicy) (controllerutil.OperationResult, error) {
 	logger := log.FromContext(ctx).WithName("karmada-client")
 
 	// Try to get existing policy
@@ -107,9 +97,12 @@ func (k *KarmadaClient) CreateOrUpdatePropagationPolicy(ctx context.Context, pol
 		if client.IgnoreNotFound(err) == nil {
 			// Policy doesn't exist, create it
 			logger.Info("Creating PropagationPolicy", "name", policy.Name, "namespace", policy.Namespace)
-			return k.Create(ctx, policy)
+			if err := k.Create(ctx, policy); err != nil {
+				return controllerutil.OperationResultNone, err
+			}
+			return controllerutil.OperationResultCreated, nil
 		}
-		return fmt.Errorf("failed to get PropagationPolicy: %w", err)
+		return controllerutil.OperationResultNone, fmt.Errorf("failed to get PropagationPolicy: %w", err)
 	}
 
 	// Policy exists, update it - preserve system-managed labels and annotations
@@ -132,7 +125,10 @@ func (k *KarmadaClient) CreateOrUpdatePropagationPolicy(ctx context.Context, pol
 		}
 	}
 
-	return k.Update(ctx, policy)
+	if err := k.Update(ctx, policy); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	return controllerutil.OperationResultUpdated, nil
 }
 
 // DeletePropagationPolicy deletes a PropagationPolicy from Karmada
@@ -143,6 +139,36 @@ func (k *KarmadaClient) DeletePropagationPolicy(ctx context.Context, policy *kar
 	return client.IgnoreNotFound(k.Delete(ctx, policy))
 }
 
+// ApplyResourceInterpreterCustomization creates the ResourceInterpreterCustomization in Karmada, or updates it if
+// its rules or labels changed, and reports which it did
+func (k *KarmadaClient) ApplyResourceInterpreterCustomization(ctx context.Context, customization *configv1alpha1.ResourceInterpreterCustomization) (controllerutil.OperationResult, error) {
+	logger := log.FromContext(ctx).WithName("karmada-client")
+
+	existing := &configv1alpha1.ResourceInterpreterCustomization{}
+	if err := k.Get(ctx, client.ObjectKeyFromObject(customization), existing); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return controllerutil.OperationResultNone, fmt.Errorf("failed to get ResourceInterpreterCustomization: %w", err)
+		}
+		logger.Info("Creating ResourceInterpreterCustomization", "name", customization.Name)
+		if err := k.Create(ctx, customization); err != nil {
+			return controllerutil.OperationResultNone, err
+		}
+		return controllerutil.OperationResultCreated, nil
+	}
+
+	if equality.Semantic.DeepEqual(existing.Spec, customization.Spec) && equality.Semantic.DeepEqual(existing.Labels, customization.Labels) {
+		return controllerutil.OperationResultNone, nil
+	}
+
+	logger.Info("Updating ResourceInterpreterCustomization", "name", customization.Name)
+	existing.Labels = customization.Labels
+	existing.Spec = customization.Spec
+	if err := k.Update(ctx, existing); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	return controllerutil.OperationResultUpdated, nil
+}
+
 // TestConnection tests the connection to Karmada
 func (k *KarmadaClient) TestConnection(ctx context.Context) error {
 	logger := log.FromContext(ctx).WithName("karmada-client")
@@ -158,6 +184,14 @@ func (k *KarmadaClient) TestConnection(ctx context.Context) error {
 	return nil
 }
 
+// ClusterProxyConfig returns a REST config that reaches the API server of a member cluster through
+// the Karmada cluster proxy, for clients that cannot build the proxy paths themselves such as informers
+func (k *KarmadaClient) ClusterProxyConfig(cluster string) *rest.Config {
+	config := rest.CopyConfig(k.config)
+	config.Host = strings.TrimSuffix(config.Host, "/") + fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy", cluster)
+	return config
+}
+
 // RESTClient returns the REST client for making proxy requests
 func (k *KarmadaClient) RESTClient() rest.Interface {
 	return k.restClient
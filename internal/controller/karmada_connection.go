@@ -0,0 +1,269 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// KarmadaKubeconfigPath is the default path where the Karmada kubeconfig is mounted
+	KarmadaKubeconfigPath = "/etc/karmada/kubeconfig"
+	// KarmadaKubeconfigSecretKey is the key of the kubeconfig in the Karmada kubeconfig Secret
+	KarmadaKubeconfigSecretKey = "kubeconfig"
+
+	// karmadaCredentialsPollInterval is how often the Karmada credentials are checked for rotation,
+	// and a failed connection is retried
+	karmadaCredentialsPollInterval = 30 * time.Second
+)
+
+// KarmadaConnectionOptions selects where the credentials of the Karmada API server are read from. The Secret is
+// used if set, then the kubeconfig file. Without either, the manager's own config is used if it points to the
+// Karmada API server.
+type KarmadaConnectionOptions struct {
+	// Kubeconfig is the path of the Karmada kubeconfig
+	Kubeconfig string
+	// Context is the kubeconfig context to use, the current context if empty
+	Context string
+	// SecretRef references a Secret holding the Karmada kubeconfig under KarmadaKubeconfigSecretKey
+	SecretRef *types.NamespacedName
+
+	// Reader reads the Secret
+	Reader client.Reader
+	// ManagerConfig is the config of the manager, used when it runs against the Karmada API server itself
+	ManagerConfig *rest.Config
+}
+
+// KarmadaConnection builds the Karmada and member cluster clients once the manager starts and rebuilds them when
+// the credentials are rotated. Reconcilers read the current clients with Clients on every reconcile.
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+type KarmadaConnection struct {
+	options KarmadaConnectionOptions
+
+	mu            sync.RWMutex
+	karmadaClient *KarmadaClient
+	memberClient  *MemberClusterClient
+	// credentials is the fingerprint of the credentials the clients were built from, to detect rotation
+	credentials []byte
+}
+
+// NewKarmadaConnection creates a connection that is started by the manager
+func NewKarmadaConnection(options KarmadaConnectionOptions) *KarmadaConnection {
+	return &KarmadaConnection{options: options}
+}
+
+// ParseKarmadaSecretRef parses a namespace/name reference to the Karmada kubeconfig Secret
+func ParseKarmadaSecretRef(ref string) (*types.NamespacedName, error) {
+	if ref == "" {
+		return nil, nil
+	}
+	namespace, name, found := strings.Cut(ref, "/")
+	if !found || namespace == "" || name == "" {
+		return nil, fmt.Errorf("invalid Secret reference %q, must be namespace/name", ref)
+	}
+	return &types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// Clients returns the current Karmada and member cluster clients, nil until the connection succeeds
+func (c *KarmadaConnection) Clients() (*KarmadaClient, *MemberClusterClient) {
+	if c == nil {
+		return nil, nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.karmadaClient, c.memberClient
+}
+
+// Start implements manager.Runnable. It connects to Karmada, retrying until it succeeds, and reconnects
+// whenever the credentials change.
+func (c *KarmadaConnection) Start(ctx context.Context) error {
+	ticker := time.NewTicker(karmadaCredentialsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		c.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh rebuilds the clients if the credentials changed since they were built. The previous clients are kept
+// if the new credentials cannot be used.
+func (c *KarmadaConnection) refresh(ctx context.Context) {
+	log := logf.FromContext(ctx).WithName("karmada-connection")
+
+	config, credentials, err := c.loadConfig(ctx)
+	if err != nil {
+		log.Error(err, "Failed to load the Karmada credentials")
+		return
+	}
+
+	c.mu.RLock()
+	unchanged := c.karmadaClient != nil && bytes.Equal(c.credentials, credentials)
+	c.mu.RUnlock()
+	if unchanged {
+		return
+	}
+
+	karmadaClient, err := NewKarmadaClient(config)
+	if err != nil {
+		log.Error(err, "Failed to initialize Karmada client")
+		return
+	}
+	if err := karmadaClient.TestConnection(ctx); err != nil {
+		log.Error(err, "Failed to connect to Karmada")
+		return
+	}
+	memberClient, err := NewMemberClusterClient(karmadaClient)
+	if err != nil {
+		log.Error(err, "Failed to initialize MemberClusterClient")
+		return
+	}
+
+	c.mu.Lock()
+	reloaded := c.karmadaClient != nil
+	c.karmadaClient, c.memberClient, c.credentials = karmadaClient, memberClient, credentials
+	c.mu.Unlock()
+	log.Info("Connected to Karmada", "endpoint", config.Host, "reloaded", reloaded)
+}
+
+// loadConfig loads the config of the Karmada API server, and a fingerprint of the credentials it uses
+func (c *KarmadaConnection) loadConfig(ctx context.Context) (*rest.Config, []byte, error) {
+	config, err := c.readConfig(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	credentials, err := credentialsFingerprint(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, credentials, nil
+}
+
+// readConfig reads the config of the Karmada API server from the Secret, the kubeconfig file or the manager's config
+func (c *KarmadaConnection) readConfig(ctx context.Context) (*rest.Config, error) {
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: c.options.Context}
+
+	if ref := c.options.SecretRef; ref != nil {
+		var secret corev1.Secret
+		if err := c.options.Reader.Get(ctx, *ref, &secret); err != nil {
+			return nil, fmt.Errorf("failed to get Karmada kubeconfig Secret %s: %w", ref, err)
+		}
+		kubeconfig, found := secret.Data[KarmadaKubeconfigSecretKey]
+		if !found {
+			return nil, fmt.Errorf("Karmada kubeconfig Secret %s has no %q key", ref, KarmadaKubeconfigSecretKey)
+		}
+		rawConfig, err := clientcmd.Load(kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Karmada kubeconfig from Secret %s: %w", ref, err)
+		}
+		config, err := clientcmd.NewNonInteractiveClientConfig(*rawConfig, c.options.Context, overrides, nil).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Karmada kubeconfig from Secret %s: %w", ref, err)
+		}
+		return config, nil
+	}
+
+	_, err := os.Stat(c.options.Kubeconfig)
+	switch {
+	case err == nil:
+		// The kubeconfig is loaded from its path so that the files it references are resolved as well
+		config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: c.options.Kubeconfig}, overrides).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Karmada kubeconfig %s: %w", c.options.Kubeconfig, err)
+		}
+		return config, nil
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("failed to read Karmada kubeconfig %s: %w", c.options.Kubeconfig, err)
+	}
+
+	if c.options.ManagerConfig != nil && servesKarmada(c.options.ManagerConfig) {
+		return c.options.ManagerConfig, nil
+	}
+	return nil, fmt.Errorf("Karmada kubeconfig not found at %s and the manager does not run against the Karmada API server", c.options.Kubeconfig)
+}
+
+// credentialsFingerprint hashes the endpoint and credentials of the config. The certificates, keys and tokens
+// a kubeconfig references by path are read, so that rotating those files is detected as well.
+func credentialsFingerprint(config *rest.Config) ([]byte, error) {
+	hash := sha256.New()
+	write := func(data []byte) {
+		// Each value is prefixed with its length so that consecutive values cannot be confused
+		_ = binary.Write(hash, binary.BigEndian, uint64(len(data)))
+		hash.Write(data)
+	}
+	writeFile := func(path string) error {
+		if path == "" {
+			write(nil)
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read Karmada credentials file %s: %w", path, err)
+		}
+		write(data)
+		return nil
+	}
+
+	tls := config.TLSClientConfig
+	for _, value := range []string{config.Host, config.Username, config.Password, config.BearerToken, tls.ServerName} {
+		write([]byte(value))
+	}
+	for _, data := range [][]byte{tls.CertData, tls.KeyData, tls.CAData} {
+		write(data)
+	}
+	for _, path := range []string{tls.CertFile, tls.KeyFile, tls.CAFile, config.BearerTokenFile} {
+		if err := writeFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return hash.Sum(nil), nil
+}
+
+// servesKarmada reports whether the API server of the config serves the Karmada policy API
+func servesKarmada(config *rest.Config) bool {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return false
+	}
+	_, err = discoveryClient.ServerResourcesForGroupVersion(karmadav1alpha1.SchemeGroupVersion.String())
+	return err == nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Karmada connection", func() {
+	ctx := context.Background()
+
+	kubeconfig := func(server string) []byte {
+		return []byte(`apiVersion: v1
+kind: Config
+current-context: karmada-apiserver
+clusters:
+- name: karmada-apiserver
+  cluster:
+    server: ` + server + `
+- name: karmada-host
+  cluster:
+    server: https://karmada-host.example.com:6443
+contexts:
+- name: karmada-apiserver
+  context:
+    cluster: karmada-apiserver
+    user: admin
+- name: karmada-host
+  context:
+    cluster: karmada-host
+    user: admin
+users:
+- name: admin
+  user:
+    token: secret-token
+`)
+	}
+
+	Context("When parsing the Secret reference", func() {
+		It("should require a namespace and a name", func() {
+			Expect(ParseKarmadaSecretRef("")).To(BeNil())
+			Expect(ParseKarmadaSecretRef("karmada-system/karmada-kubeconfig")).To(Equal(
+				&types.NamespacedName{Namespace: "karmada-system", Name: "karmada-kubeconfig"}))
+			for _, ref := range []string{"karmada-kubeconfig", "/karmada-kubeconfig", "karmada-system/"} {
+				_, err := ParseKarmadaSecretRef(ref)
+				Expect(err).To(HaveOccurred(), ref)
+			}
+		})
+	})
+
+	Context("When loading the credentials", func() {
+		It("should read the kubeconfig file with the selected context", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "kubeconfig")
+			Expect(os.WriteFile(path, kubeconfig("https://karmada.example.com:5443"), 0o600)).To(Succeed())
+
+			connection := NewKarmadaConnection(KarmadaConnectionOptions{Kubeconfig: path})
+			config, credentials, err := connection.loadConfig(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Host).To(Equal("https://karmada.example.com:5443"))
+			Expect(config.BearerToken).To(Equal("secret-token"))
+
+			connection = NewKarmadaConnection(KarmadaConnectionOptions{Kubeconfig: path, Context: "karmada-host"})
+			config, _, err = connection.loadConfig(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Host).To(Equal("https://karmada-host.example.com:6443"))
+
+			// Rotated credentials are detected from the content of the file
+			Expect(os.WriteFile(path, kubeconfig("https://karmada.example.com:5444"), 0o600)).To(Succeed())
+			_, rotated, err := NewKarmadaConnection(KarmadaConnectionOptions{Kubeconfig: path}).loadConfig(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rotated).NotTo(Equal(credentials))
+		})
+
+		It("should detect the rotation of the files referenced by the kubeconfig", func() {
+			dir := GinkgoT().TempDir()
+			path := filepath.Join(dir, "kubeconfig")
+			Expect(os.WriteFile(path, []byte(`apiVersion: v1
+kind: Config
+current-context: karmada-apiserver
+clusters:
+- name: karmada-apiserver
+  cluster:
+    server: https://karmada.example.com:5443
+    certificate-authority: ca.crt
+contexts:
+- name: karmada-apiserver
+  context:
+    cluster: karmada-apiserver
+    user: admin
+users:
+- name: admin
+  user:
+    client-certificate: admin.crt
+    client-key: admin.key
+`), 0o600)).To(Succeed())
+			for _, file := range []string{"ca.crt", "admin.crt", "admin.key"} {
+				Expect(os.WriteFile(filepath.Join(dir, file), []byte(file+" 1"), 0o600)).To(Succeed())
+			}
+
+			connection := NewKarmadaConnection(KarmadaConnectionOptions{Kubeconfig: path})
+			config, credentials, err := connection.loadConfig(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.CertFile).To(Equal(filepath.Join(dir, "admin.crt")))
+
+			_, unchanged, err := connection.loadConfig(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unchanged).To(Equal(credentials))
+
+			for _, file := range []string{"admin.crt", "admin.key"} {
+				Expect(os.WriteFile(filepath.Join(dir, file), []byte(file+" 2"), 0o600)).To(Succeed())
+			}
+			_, rotated, err := connection.loadConfig(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rotated).NotTo(Equal(credentials))
+		})
+
+		It("should read the kubeconfig from the Secret", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "karmada-kubeconfig", Namespace: "default"},
+				Data:       map[string][]byte{"config": kubeconfig("https://karmada.example.com:5443")},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			})
+
+			connection := NewKarmadaConnection(KarmadaConnectionOptions{
+				Kubeconfig: KarmadaKubeconfigPath,
+				SecretRef:  &types.NamespacedName{Namespace: "default", Name: "karmada-kubeconfig"},
+				Reader:     k8sClient,
+			})
+			_, _, err := connection.loadConfig(ctx)
+			Expect(err).To(MatchError(ContainSubstring(`has no "kubeconfig" key`)))
+
+			secret.Data = map[string][]byte{KarmadaKubeconfigSecretKey: secret.Data["config"]}
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			config, _, err := connection.loadConfig(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Host).To(Equal("https://karmada.example.com:5443"))
+		})
+
+		It("should fail without a kubeconfig outside of Karmada", func() {
+			connection := NewKarmadaConnection(KarmadaConnectionOptions{Kubeconfig: filepath.Join(GinkgoT().TempDir(), "missing")})
+			_, _, err := connection.loadConfig(ctx)
+			Expect(err).To(MatchError(ContainSubstring("Karmada kubeconfig not found")))
+
+			karmadaClient, memberClient := connection.Clients()
+			Expect(karmadaClient).To(BeNil())
+			Expect(memberClient).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// KubeletClient calls the kubelet checkpoint API through the API server node proxy
+type KubeletClient struct {
+	restClient rest.Interface
+}
+
+// checkpointResponse is the body returned by the kubelet checkpoint endpoint
+type checkpointResponse struct {
+	Items []string `json:"items"`
+}
+
+// NewKubeletClient creates a new kubelet client using the given REST config
+func NewKubeletClient(config *rest.Config) (*KubeletClient, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+
+	return &KubeletClient{
+		restClient: clientset.CoreV1().RESTClient(),
+	}, nil
+}
+
+// CheckpointContainer checkpoints a container and returns the paths of the archives written on the node
+func (k *KubeletClient) CheckpointContainer(ctx context.Context, nodeName, namespace, podName, containerName string, timeout time.Duration) ([]string, error) {
+	logger := log.FromContext(ctx)
+
+	// The URL pattern is: /api/v1/nodes/{node}/proxy/checkpoint/{namespace}/{pod}/{container}
+	req := k.restClient.Post().
+		AbsPath(fmt.Sprintf("/api/v1/nodes/%s/proxy/checkpoint/%s/%s/%s",
+			nodeName, namespace, podName, containerName))
+
+	if timeout > 0 {
+		req = req.Param("timeout", strconv.Itoa(int(timeout.Seconds())))
+	}
+
+	body, err := req.DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to checkpoint container %s in pod %s/%s on node %s: %w",
+			containerName, namespace, podName, nodeName, err)
+	}
+
+	var response checkpointResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint response from node %s: %w", nodeName, err)
+	}
+
+	if len(response.Items) == 0 {
+		return nil, fmt.Errorf("kubelet on node %s returned no checkpoint archive for container %s in pod %s/%s",
+			nodeName, containerName, namespace, podName)
+	}
+
+	logger.Info("Successfully checkpointed container",
+		"node", nodeName, "namespace", namespace, "pod", podName, "container", containerName, "archives", response.Items)
+
+	return response.Items, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+)
+
+var _ = Describe("Kubelet client", func() {
+	ctx := context.Background()
+
+	// newKubeletClient serves the node proxy of the API server with the handler
+	newKubeletClient := func(handler http.HandlerFunc) *KubeletClient {
+		server := httptest.NewServer(handler)
+		DeferCleanup(server.Close)
+
+		kubeletClient, err := NewKubeletClient(&rest.Config{Host: server.URL})
+		Expect(err).NotTo(HaveOccurred())
+		return kubeletClient
+	}
+
+	Context("When checkpointing a container", func() {
+		It("should post to the kubelet checkpoint API and return the archives", func() {
+			var method, path, timeout string
+			kubeletClient := newKubeletClient(func(w http.ResponseWriter, req *http.Request) {
+				method, path, timeout = req.Method, req.URL.Path, req.URL.Query().Get("timeout")
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"items":["/var/lib/kubelet/checkpoints/checkpoint-web-0_default-app-2025-01-01T10:00:00Z.tar"]}`))
+			})
+
+			archives, err := kubeletClient.CheckpointContainer(ctx, "node-1", "default", "web-0", "app", 4*time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(archives).To(Equal([]string{"/var/lib/kubelet/checkpoints/checkpoint-web-0_default-app-2025-01-01T10:00:00Z.tar"}))
+			Expect(method).To(Equal(http.MethodPost))
+			Expect(path).To(Equal("/api/v1/nodes/node-1/proxy/checkpoint/default/web-0/app"))
+			Expect(timeout).To(Equal("240"))
+		})
+
+		It("should escape the names in the checkpoint path", func() {
+			var path, escapedPath string
+			kubeletClient := newKubeletClient(func(w http.ResponseWriter, req *http.Request) {
+				path, escapedPath = req.URL.Path, req.URL.EscapedPath()
+				_, _ = w.Write([]byte(`{"items":["/var/lib/kubelet/checkpoints/app.tar"]}`))
+			})
+
+			_, err := kubeletClient.CheckpointContainer(ctx, "node-1", "default", "web-0", "app #1", 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(path).To(Equal("/api/v1/nodes/node-1/proxy/checkpoint/default/web-0/app #1"))
+			Expect(escapedPath).To(Equal("/api/v1/nodes/node-1/proxy/checkpoint/default/web-0/app%20%231"))
+		})
+
+		It("should report the errors of the kubelet", func() {
+			kubeletClient := newKubeletClient(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("checkpointing is only supported with CRI-O and containerd"))
+			})
+
+			_, err := kubeletClient.CheckpointContainer(ctx, "node-1", "default", "web-0", "app", 0)
+			Expect(err).To(MatchError(ContainSubstring("failed to checkpoint container app in pod default/web-0 on node node-1")))
+		})
+
+		It("should reject responses without archives", func() {
+			body := `{"items":[]}`
+			kubeletClient := newKubeletClient(func(w http.ResponseWriter, req *http.Request) {
+				_, _ = w.Write([]byte(body))
+			})
+
+			_, err := kubeletClient.CheckpointContainer(ctx, "node-1", "default", "web-0", "app", 0)
+			Expect(err).To(MatchError(ContainSubstring("returned no checkpoint archive")))
+
+			body = `not json`
+			_, err = kubeletClient.CheckpointContainer(ctx, "node-1", "default", "web-0", "app", 0)
+			Expect(err).To(MatchError(ContainSubstring("failed to decode checkpoint response from node node-1")))
+		})
+	})
+})
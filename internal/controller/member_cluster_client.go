@@ -6,9 +6,14 @@ import (
 	"os"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
 )
 
 // MemberClusterClient manages connections to Karmada member clusters using aggregated API
@@ -59,25 +64,27 @@ func (m *MemberClusterClient) GetPodFromCluster(ctx context.Context, clusterName
 	return &pod, nil
 }
 
-// UpdatePodInCluster updates a pod in the specified member cluster using Karmada aggregated API
-func (m *MemberClusterClient) UpdatePodInCluster(ctx context.Context, clusterName string, pod *corev1.Pod) error {
+// PatchPodInCluster patches a pod in the specified member cluster using Karmada aggregated API.
+// The patch is sent under the operator's field manager, so that only the fields it sets are owned by the operator.
+func (m *MemberClusterClient) PatchPodInCluster(ctx context.Context, clusterName, namespace, podName string, patchType types.PatchType, patch []byte) error {
 	logger := log.FromContext(ctx)
 
-	// Use Karmada aggregated API to proxy update request to member cluster
+	// Use Karmada aggregated API to proxy patch request to member cluster
 	restClient := m.karmadaClient.RESTClient()
 
-	result := restClient.Put().
+	result := restClient.Patch(patchType).
 		AbsPath(fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy/api/v1/namespaces/%s/pods/%s",
-			clusterName, pod.Namespace, pod.Name)).
-		Body(pod).
+			clusterName, namespace, podName)).
+		Param("fieldManager", FieldManager).
+		Body(patch).
 		Do(ctx)
 
 	if err := result.Error(); err != nil {
-		return fmt.Errorf("failed to update pod %s/%s on cluster %s: %w", pod.Namespace, pod.Name, clusterName, err)
+		return fmt.Errorf("failed to patch pod %s/%s on cluster %s: %w", namespace, podName, clusterName, err)
 	}
 
-	logger.Info("Successfully updated pod on member cluster",
-		"cluster", clusterName, "namespace", pod.Namespace, "pod", pod.Name)
+	logger.Info("Successfully patched pod on member cluster",
+		"cluster", clusterName, "namespace", namespace, "pod", podName)
 
 	return nil
 }
@@ -110,6 +117,106 @@ func (m *MemberClusterClient) ListPodsFromCluster(ctx context.Context, clusterNa
 	return &podList, nil
 }
 
+// ListNodesFromCluster lists the nodes of the specified member cluster using Karmada aggregated API
+func (m *MemberClusterClient) ListNodesFromCluster(ctx context.Context, clusterName string) (*corev1.NodeList, error) {
+	var nodeList corev1.NodeList
+
+	result := m.karmadaClient.RESTClient().Get().
+		AbsPath(fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy/api/v1/nodes", clusterName)).
+		Do(ctx)
+
+	if err := result.Into(&nodeList); err != nil {
+		return nil, fmt.Errorf("failed to list nodes from cluster %s: %w", clusterName, err)
+	}
+
+	return &nodeList, nil
+}
+
+// GetKubeletMetricsFromCluster gets the Prometheus metrics of the kubelet of a node in the specified member cluster
+func (m *MemberClusterClient) GetKubeletMetricsFromCluster(ctx context.Context, clusterName, nodeName string) ([]byte, error) {
+	data, err := m.karmadaClient.RESTClient().Get().
+		AbsPath(fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy/api/v1/nodes/%s/proxy/metrics", clusterName, nodeName)).
+		DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubelet metrics of node %s from cluster %s: %w", nodeName, clusterName, err)
+	}
+
+	return data, nil
+}
+
+// GetObjectFromCluster gets an object of any kind from the specified member cluster using Karmada aggregated API
+func (m *MemberClusterClient) GetObjectFromCluster(ctx context.Context, clusterName string, mapping *meta.RESTMapping, namespace, name string) (*unstructured.Unstructured, error) {
+	resource := mapping.Resource
+
+	// The core group is served under /api, the other groups under /apis
+	path := fmt.Sprintf("/apis/%s/%s", resource.Group, resource.Version)
+	if resource.Group == "" {
+		path = fmt.Sprintf("/api/%s", resource.Version)
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		path = fmt.Sprintf("%s/namespaces/%s", path, namespace)
+	}
+
+	data, err := m.karmadaClient.RESTClient().Get().
+		AbsPath(fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy%s/%s/%s", clusterName, path, resource.Resource, name)).
+		DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s from cluster %s: %w", mapping.GroupVersionKind.Kind, namespace, name, clusterName, err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("failed to decode %s %s/%s from cluster %s: %w", mapping.GroupVersionKind.Kind, namespace, name, clusterName, err)
+	}
+	return obj, nil
+}
+
+// GetCheckpointBackupFromCluster gets a CheckpointBackup, including the status reported by the agent, from the specified member cluster
+func (m *MemberClusterClient) GetCheckpointBackupFromCluster(ctx context.Context, clusterName, namespace, name string) (*migrationv1.CheckpointBackup, error) {
+	var backup migrationv1.CheckpointBackup
+
+	result := m.karmadaClient.RESTClient().Get().
+		AbsPath(fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy/apis/migration.dcnlab.com/v1/namespaces/%s/checkpointbackups/%s",
+			clusterName, namespace, name)).
+		Do(ctx)
+
+	if err := result.Into(&backup); err != nil {
+		return nil, fmt.Errorf("failed to get CheckpointBackup %s/%s from cluster %s: %w", namespace, name, clusterName, err)
+	}
+
+	return &backup, nil
+}
+
+// DeleteCheckpointBackupFromCluster deletes the copy of a CheckpointBackup propagated to the specified member cluster
+func (m *MemberClusterClient) DeleteCheckpointBackupFromCluster(ctx context.Context, clusterName, namespace, name string) error {
+	result := m.karmadaClient.RESTClient().Delete().
+		AbsPath(fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy/apis/migration.dcnlab.com/v1/namespaces/%s/checkpointbackups/%s",
+			clusterName, namespace, name)).
+		Do(ctx)
+
+	if err := result.Error(); err != nil {
+		return fmt.Errorf("failed to delete CheckpointBackup %s/%s from cluster %s: %w", namespace, name, clusterName, err)
+	}
+
+	return nil
+}
+
+// GetCheckpointRestoreFromCluster gets a CheckpointRestore, including the status reported by the agent, from the specified member cluster
+func (m *MemberClusterClient) GetCheckpointRestoreFromCluster(ctx context.Context, clusterName, namespace, name string) (*migrationv1.CheckpointRestore, error) {
+	var restore migrationv1.CheckpointRestore
+
+	result := m.karmadaClient.RESTClient().Get().
+		AbsPath(fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy/apis/migration.dcnlab.com/v1/namespaces/%s/checkpointrestores/%s",
+			clusterName, namespace, name)).
+		Do(ctx)
+
+	if err := result.Into(&restore); err != nil {
+		return nil, fmt.Errorf("failed to get CheckpointRestore %s/%s from cluster %s: %w", namespace, name, clusterName, err)
+	}
+
+	return &restore, nil
+}
+
 // TestClusterConnection tests connectivity to a member cluster using Karmada aggregated API
 func (m *MemberClusterClient) TestClusterConnection(ctx context.Context, clusterName string) error {
 	logger := log.FromContext(ctx)
@@ -153,8 +260,8 @@ func (m *MemberClusterClient) EnsureNamespace(ctx context.Context, clusterName,
 		ObjectMeta: metav1.ObjectMeta{
 			Name: namespace,
 			Labels: map[string]string{
-				"created-by": "stateful-migration-operator",
-				"cluster":    clusterName,
+				CreatedByLabel: CreatedByValue,
+				"cluster":      clusterName,
 			},
 		},
 	}
@@ -172,62 +279,72 @@ func (m *MemberClusterClient) EnsureNamespace(ctx context.Context, clusterName,
 	return nil
 }
 
-// EnsureCRD ensures the CheckpointBackup CRD exists on the member cluster
+// memberClusterCRD describes a CRD the agent on member clusters relies on
+type memberClusterCRD struct {
+	name     string
+	fileName string
+	embedded string
+}
+
+// memberClusterCRDs are the CRDs installed on every member cluster
+var memberClusterCRDs = []memberClusterCRD{
+	{
+		name:     "checkpointbackups.migration.dcnlab.com",
+		fileName: "migration.dcnlab.com_checkpointbackups.yaml",
+		embedded: CheckpointBackupCRDYAML,
+	},
+	{
+		name:     "checkpointrestores.migration.dcnlab.com",
+		fileName: "migration.dcnlab.com_checkpointrestores.yaml",
+		embedded: CheckpointRestoreCRDYAML,
+	},
+}
+
+// EnsureCRD ensures the CheckpointBackup and CheckpointRestore CRDs exist on the member cluster and match the operator's schema
 func (m *MemberClusterClient) EnsureCRD(ctx context.Context, clusterName string) error {
 	logger := log.FromContext(ctx)
 
-	// Check if CheckpointBackup CRD exists
 	restClient := m.karmadaClient.RESTClient()
 
-	result := restClient.Get().
-		AbsPath(fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy/apis/apiextensions.k8s.io/v1/customresourcedefinitions/checkpointbackups.migration.dcnlab.com", clusterName)).
-		Do(ctx)
-
-	if result.Error() == nil {
-		logger.Info("CheckpointBackup CRD already exists on member cluster", "cluster", clusterName)
-		return nil
-	}
-
-	logger.Info("CheckpointBackup CRD not found, installing on member cluster", "cluster", clusterName)
+	for _, crd := range memberClusterCRDs {
+		// Get the CRD definition - try multiple sources
+		crdData := m.getCRDDefinition(crd)
 
-	// Get the CRD definition - try multiple sources
-	crdData, err := m.getCRDDefinition()
-	if err != nil {
-		return fmt.Errorf("failed to get CheckpointBackup CRD definition: %w", err)
-	}
+		// Server-side apply the CRD so that clusters with an older schema are upgraded as well
+		applyResult := restClient.Patch(types.ApplyPatchType).
+			AbsPath(fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy/apis/apiextensions.k8s.io/v1/customresourcedefinitions/%s", clusterName, crd.name)).
+			Param("fieldManager", FieldManager).
+			Param("force", "true").
+			Body([]byte(crdData)).
+			Do(ctx)
 
-	// Apply the CRD to the member cluster
-	createResult := restClient.Post().
-		AbsPath(fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy/apis/apiextensions.k8s.io/v1/customresourcedefinitions", clusterName)).
-		SetHeader("Content-Type", "application/yaml").
-		Body([]byte(crdData)).
-		Do(ctx)
+		if err := applyResult.Error(); err != nil {
+			return fmt.Errorf("failed to apply CRD %s on cluster %s: %w", crd.name, clusterName, err)
+		}
 
-	if err := createResult.Error(); err != nil {
-		return fmt.Errorf("failed to install CheckpointBackup CRD on cluster %s: %w", clusterName, err)
+		logger.Info("Successfully applied CRD on member cluster", "cluster", clusterName, "crd", crd.name)
 	}
 
-	logger.Info("Successfully installed CheckpointBackup CRD on member cluster", "cluster", clusterName)
 	return nil
 }
 
-// getCRDDefinition gets the CheckpointBackup CRD definition from various sources
-func (m *MemberClusterClient) getCRDDefinition() (string, error) {
+// getCRDDefinition gets a CRD definition from various sources
+func (m *MemberClusterClient) getCRDDefinition(crd memberClusterCRD) string {
 	// Try different sources for the CRD definition
 
 	// 1. Try mounted file (for when CRDs are mounted as ConfigMap or volume)
 	mountedPaths := []string{
-		"/etc/crds/migration.dcnlab.com_checkpointbackups.yaml",
-		"/app/crds/migration.dcnlab.com_checkpointbackups.yaml",
-		"config/crd/bases/migration.dcnlab.com_checkpointbackups.yaml",
+		"/etc/crds/" + crd.fileName,
+		"/app/crds/" + crd.fileName,
+		"config/crd/bases/" + crd.fileName,
 	}
 
 	for _, path := range mountedPaths {
 		if data, err := os.ReadFile(path); err == nil {
-			return string(data), nil
+			return string(data)
 		}
 	}
 
 	// 2. Use embedded CRD definition as fallback
-	return CheckpointBackupCRDYAML, nil
+	return crd.embedded
 }
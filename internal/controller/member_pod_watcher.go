@@ -0,0 +1,220 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// memberPodEventBuffer is the number of pod events the informers can send ahead of the controller
+const memberPodEventBuffer = 1024
+
+// MemberPod is a pod on a member cluster
+type MemberPod struct {
+	Cluster string
+	Pod     *corev1.Pod
+}
+
+// memberPodScope is a namespace of a member cluster whose pods are watched
+type memberPodScope struct {
+	Cluster   string
+	Namespace string
+}
+
+// String returns the value indexing the StatefulMigrations that watch the scope
+func (s memberPodScope) String() string {
+	return s.Cluster + "/" + s.Namespace
+}
+
+// memberPodWatcher watches the pods of the member clusters through the Karmada cluster proxy and sends an
+// event for every pod that was added, deleted, rescheduled or changed phase or labels. A namespace of a
+// cluster is watched as long as some StatefulMigration backs up pods of its workloads there.
+type memberPodWatcher struct {
+	events chan event.TypedGenericEvent[MemberPod]
+
+	mu sync.Mutex
+	// ctx is the context of the manager, set once the watcher is started
+	ctx           context.Context
+	karmadaClient *KarmadaClient
+	// scopes holds the namespaces of the clusters each StatefulMigration needs watched
+	scopes map[types.NamespacedName][]memberPodScope
+	// informers holds the cancel function of the informer of each watched namespace
+	informers map[memberPodScope]context.CancelFunc
+}
+
+// newMemberPodWatcher creates a watcher that is started by the manager
+func newMemberPodWatcher() *memberPodWatcher {
+	return &memberPodWatcher{
+		events:    make(chan event.TypedGenericEvent[MemberPod], memberPodEventBuffer),
+		scopes:    make(map[types.NamespacedName][]memberPodScope),
+		informers: make(map[memberPodScope]context.CancelFunc),
+	}
+}
+
+// Start implements manager.Runnable. It starts the informers of the clusters requested so far and stops
+// every informer when the manager stops.
+func (w *memberPodWatcher) Start(ctx context.Context) error {
+	w.mu.Lock()
+	w.ctx = ctx
+	w.syncInformers()
+	w.mu.Unlock()
+
+	<-ctx.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for scope, cancel := range w.informers {
+		cancel()
+		delete(w.informers, scope)
+	}
+	return nil
+}
+
+// watch records the namespaces of the clusters whose pods the StatefulMigration needs to be notified about,
+// starting and stopping informers as needed. No scopes releases the namespaces of the StatefulMigration.
+func (w *memberPodWatcher) watch(key types.NamespacedName, karmadaClient *KarmadaClient, scopes []memberPodScope) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Informers of a previous Karmada client are restarted with the rotated credentials
+	if karmadaClient != nil && karmadaClient != w.karmadaClient {
+		for scope, cancel := range w.informers {
+			cancel()
+			delete(w.informers, scope)
+		}
+		w.karmadaClient = karmadaClient
+	}
+	if len(scopes) == 0 {
+		delete(w.scopes, key)
+	} else {
+		w.scopes[key] = slices.Clone(scopes)
+	}
+	w.syncInformers()
+}
+
+// syncInformers runs an informer for every namespace requested by some StatefulMigration. It must be called with the lock held.
+func (w *memberPodWatcher) syncInformers() {
+	if w.ctx == nil || w.karmadaClient == nil {
+		return
+	}
+
+	wanted := make(map[memberPodScope]bool)
+	for _, scopes := range w.scopes {
+		for _, scope := range scopes {
+			wanted[scope] = true
+		}
+	}
+
+	for scope, cancel := range w.informers {
+		if !wanted[scope] {
+			cancel()
+			delete(w.informers, scope)
+		}
+	}
+	for _, scope := range slices.SortedFunc(maps.Keys(wanted), func(a, b memberPodScope) int {
+		return strings.Compare(a.String(), b.String())
+	}) {
+		if _, running := w.informers[scope]; running {
+			continue
+		}
+		if err := w.startInformer(scope); err != nil {
+			logf.FromContext(w.ctx).Error(err, "Failed to watch pods on member cluster", "cluster", scope.Cluster, "namespace", scope.Namespace)
+		}
+	}
+}
+
+// startInformer starts an informer on the pods of the namespace. It must be called with the lock held.
+func (w *memberPodWatcher) startInformer(scope memberPodScope) error {
+	cluster := scope.Cluster
+	clientset, err := kubernetes.NewForConfig(w.karmadaClient.ClusterProxyConfig(cluster))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(w.ctx)
+	informer := cache.NewSharedIndexInformer(
+		cache.NewListWatchFromClient(clientset.CoreV1().RESTClient(), "pods", scope.Namespace, fields.Everything()),
+		&corev1.Pod{}, 0, cache.Indexers{})
+	// Only a few fields of the pods are looked at, managed fields are dropped to save memory
+	if err := informer.SetTransform(func(obj interface{}) (interface{}, error) {
+		if pod, ok := obj.(*corev1.Pod); ok {
+			pod.ManagedFields = nil
+		}
+		return obj, nil
+	}); err != nil {
+		cancel()
+		return err
+	}
+
+	send := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			return
+		}
+		select {
+		case w.events <- event.TypedGenericEvent[MemberPod]{Object: MemberPod{Cluster: cluster, Pod: pod}}:
+		case <-ctx.Done():
+		}
+	}
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: send,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPod, oldOK := oldObj.(*corev1.Pod)
+			newPod, newOK := newObj.(*corev1.Pod)
+			if oldOK && newOK && memberPodChanged(oldPod, newPod) {
+				send(newPod)
+			}
+		},
+		DeleteFunc: send,
+	}); err != nil {
+		cancel()
+		return err
+	}
+
+	go informer.Run(ctx.Done())
+	w.informers[scope] = cancel
+	logf.FromContext(w.ctx).Info("Watching pods on member cluster", "cluster", cluster, "namespace", scope.Namespace)
+	return nil
+}
+
+// memberPodChanged reports whether a pod changed in a way that affects its CheckpointBackup
+func memberPodChanged(oldPod, newPod *corev1.Pod) bool {
+	return oldPod.Spec.NodeName != newPod.Spec.NodeName ||
+		oldPod.Status.Phase != newPod.Status.Phase ||
+		(oldPod.DeletionTimestamp == nil) != (newPod.DeletionTimestamp == nil) ||
+		!equality.Semantic.DeepEqual(oldPod.Labels, newPod.Labels)
+}
@@ -17,24 +17,39 @@ limitations under the License.
 package controller
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"path"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
 	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
 )
 
@@ -43,6 +58,32 @@ const (
 	CheckpointMigrationLabel = "checkpoint-migration.dcn.io"
 	// Finalizer to ensure proper cleanup
 	MigrationBackupFinalizer = "migrationbackup.migration.dcnlab.com/finalizer"
+	// FieldManager is the field manager used for server-side apply requests
+	FieldManager = "stateful-migration-operator"
+
+	// DefaultMigrationNamespace is the namespace propagated to the source clusters unless configured otherwise
+	DefaultMigrationNamespace = "stateful-migration"
+
+	// DefaultResyncPeriod is how often a StatefulMigration is reconciled when no watched change triggers it
+	DefaultResyncPeriod = 10 * time.Minute
+
+	// workloadIndexKey indexes StatefulMigrations by the workloads they enroll
+	workloadIndexKey = ".spec.resourceRef"
+	// memberPodScopeIndexKey indexes StatefulMigrations by the namespaces of the source clusters they watch pods in
+	memberPodScopeIndexKey = ".status.sourceClusters.namespaces"
+)
+
+// Reasons used in StatefulMigration events about its backups
+const (
+	ReasonWorkloadLabeled          = "WorkloadLabeled"
+	ReasonWorkloadReleased         = "WorkloadReleased"
+	ReasonBackupCreated            = "BackupCreated"
+	ReasonBackupDeleted            = "BackupDeleted"
+	ReasonNamespaceCreated         = "NamespaceCreated"
+	ReasonPropagationPolicyCreated = "PropagationPolicyCreated"
+	ReasonPropagationPolicyUpdated = "PropagationPolicyUpdated"
+	ReasonCRDsInstalled            = "CRDsInstalled"
+	ReasonCRDInstallFailed         = "CRDInstallFailed"
 )
 
 // MigrationBackupReconciler reconciles a StatefulMigration object
@@ -51,6 +92,24 @@ type MigrationBackupReconciler struct {
 	Scheme              *runtime.Scheme
 	KarmadaClient       *KarmadaClient
 	MemberClusterClient *MemberClusterClient
+	Recorder            record.EventRecorder
+	// Karmada provides the Karmada and member cluster clients, overriding KarmadaClient and MemberClusterClient
+	Karmada *KarmadaConnection
+	// MigrationNamespace is the namespace propagated to the source clusters of StatefulMigrations that do not
+	// set their own. Defaults to DefaultMigrationNamespace.
+	MigrationNamespace string
+	// WorkloadMarker is how enrolled workloads are marked, WorkloadMarkerLabel (the default) or WorkloadMarkerAnnotation
+	WorkloadMarker string
+	// ResyncPeriod is how often a StatefulMigration is reconciled in case a change was missed.
+	// Zero disables the periodic resync.
+	ResyncPeriod time.Duration
+
+	// crdClusters holds the member clusters the CRDs were applied to since the operator started
+	crdClusters sync.Map
+	// preflights holds the last pre-flight result of each member cluster, shared by the StatefulMigrations
+	preflights sync.Map
+	// podWatcher notifies the reconciler of pod changes on the source clusters
+	podWatcher *memberPodWatcher
 }
 
 // +kubebuilder:rbac:groups=migration.dcnlab.com,resources=statefulmigrations,verbs=get;list;watch;create;update;patch;delete
@@ -59,7 +118,12 @@ type MigrationBackupReconciler struct {
 // +kubebuilder:rbac:groups=migration.dcnlab.com,resources=checkpointbackups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=migration.dcnlab.com,resources=checkpointbackups/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=apps,resources=replicasets;daemonsets,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments/scale;statefulsets/scale;replicasets/scale,verbs=get
+// +kubebuilder:rbac:groups=argoproj.io,resources=rollouts,verbs=get;update;patch
+// +kubebuilder:rbac:groups=argoproj.io,resources=rollouts/scale,verbs=get
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=work.karmada.io,resources=resourcebindings,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
@@ -68,33 +132,10 @@ type MigrationBackupReconciler struct {
 func (r *MigrationBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
-	// Initialize Karmada client if not already done
-	if r.KarmadaClient == nil {
-		karmadaClient, err := NewKarmadaClient()
-		if err != nil {
-			log.Error(err, "Failed to initialize Karmada client")
-			// Continue without Karmada client - PropagationPolicies will be skipped
-			log.Info("Continuing without Karmada client - PropagationPolicies will be skipped")
-		} else {
-			r.KarmadaClient = karmadaClient
-			log.Info("Successfully initialized Karmada client")
-
-			// Test Karmada connection
-			if err := r.KarmadaClient.TestConnection(ctx); err != nil {
-				log.Error(err, "Failed to connect to Karmada")
-			}
-
-			// Initialize MemberClusterClient
-			if r.MemberClusterClient == nil {
-				memberClient, err := NewMemberClusterClient(r.KarmadaClient)
-				if err != nil {
-					log.Error(err, "Failed to initialize MemberClusterClient")
-				} else {
-					r.MemberClusterClient = memberClient
-					log.Info("Successfully initialized MemberClusterClient")
-				}
-			}
-		}
+	// Use the current Karmada clients, which are rebuilt when the credentials rotate.
+	// Without them, PropagationPolicies and the member clusters are skipped.
+	if r.Karmada != nil {
+		r.KarmadaClient, r.MemberClusterClient = r.Karmada.Clients()
 	}
 
 	// Fetch the StatefulMigration instance
@@ -102,6 +143,7 @@ func (r *MigrationBackupReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	if err := r.Get(ctx, req.NamespacedName, &statefulMigration); err != nil {
 		if errors.IsNotFound(err) {
 			log.Info("StatefulMigration resource not found. Ignoring since object must be deleted")
+			r.podWatcher.watch(req.NamespacedName, nil, nil)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "Failed to get StatefulMigration")
@@ -122,68 +164,299 @@ func (r *MigrationBackupReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return r.reconcileDelete(ctx, &statefulMigration)
 	}
 
-	// Handle normal reconciliation
-	return r.reconcileNormal(ctx, &statefulMigration)
+	// Handle normal reconciliation, then record the pre-flight results, backup summary and conditions
+	originalStatus := statefulMigration.Status.DeepCopy()
+	result, err := r.reconcileNormal(ctx, &statefulMigration)
+
+	if !equality.Semantic.DeepEqual(&statefulMigration.Status, originalStatus) {
+		if updateErr := r.Status().Update(ctx, &statefulMigration); updateErr != nil {
+			log.Error(updateErr, "Failed to update StatefulMigration status")
+			if err == nil {
+				err = updateErr
+			}
+		}
+	}
+	return result, err
 }
 
 // reconcileNormal handles the normal reconciliation logic
 func (r *MigrationBackupReconciler) reconcileNormal(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
-	// Step 1: Add label to target resource
-	if err := r.addLabelToTargetResource(ctx, statefulMigration); err != nil {
-		log.Error(err, "Failed to add label to target resource")
+	// Step 1: Enroll the workloads, resolve their source clusters and add the label to them
+	workloads, clusters, err := r.reconcileWorkloads(ctx, statefulMigration)
+	if err != nil {
+		log.Error(err, "Failed to enroll workloads")
 		return ctrl.Result{}, err
 	}
 
-	// Step 2: Discover pods from the target resource
-	pods, err := r.getPodsFromResourceRef(ctx, statefulMigration)
-	if err != nil {
-		log.Error(err, "Failed to get pods from resource reference")
-		return ctrl.Result{}, err
+	// Step 2: Discover the pods of each workload on each source cluster
+	discovered := make([]map[string][]corev1.Pod, len(workloads))
+	for i, workload := range workloads {
+		if discovered[i], err = r.getPodsFromResourceRef(ctx, workload, clusters); err != nil {
+			log.Error(err, "Failed to get pods from resource reference", "workload", workload.Name)
+			return ctrl.Result{}, err
+		}
 	}
 
-	// Step 3: Ensure stateful-migration namespace on Karmada and propagate to clusters
+	// Pod changes on the source clusters trigger the next reconciliation
+	if r.KarmadaClient != nil {
+		r.podWatcher.watch(client.ObjectKeyFromObject(statefulMigration), r.KarmadaClient, memberPodScopes(statefulMigration, clusters))
+	}
+
+	// Step 3: Ensure the migration namespace on Karmada and propagate it to the source clusters
 	if err := r.ensureStatefulMigrationNamespace(ctx, statefulMigration); err != nil {
-		log.Error(err, "Failed to ensure stateful-migration namespace")
+		log.Error(err, "Failed to ensure migration namespace")
 		return ctrl.Result{}, err
 	}
 
-	// Step 4: Ensure CheckpointBackup CRD on member clusters
-	for _, cluster := range statefulMigration.Spec.SourceClusters {
+	// Step 4: Ensure CheckpointBackup and CheckpointRestore CRDs on member clusters
+	for _, cluster := range clusters {
 		if r.MemberClusterClient != nil {
-			// Ensure CheckpointBackup CRD exists on member cluster
+			// Ensure the CRDs exist on member cluster
 			if err := r.MemberClusterClient.EnsureCRD(ctx, cluster); err != nil {
-				log.Error(err, "Failed to ensure CheckpointBackup CRD on cluster", "cluster", cluster)
+				log.Error(err, "Failed to ensure CRDs on cluster", "cluster", cluster)
+				r.Recorder.Eventf(statefulMigration, corev1.EventTypeWarning, ReasonCRDInstallFailed,
+					"Failed to install the CheckpointBackup and CheckpointRestore CRDs on cluster %s: %v", cluster, err)
 				return ctrl.Result{}, err
 			}
+			if _, applied := r.crdClusters.LoadOrStore(cluster, true); !applied {
+				r.Recorder.Eventf(statefulMigration, corev1.EventTypeNormal, ReasonCRDsInstalled,
+					"Installed the CheckpointBackup and CheckpointRestore CRDs on cluster %s", cluster)
+			}
 		}
 	}
 
-	// Step 5: For each source cluster, create/update CheckpointBackup resources for each pod
-	for _, cluster := range statefulMigration.Spec.SourceClusters {
-		for _, pod := range pods {
-			if err := r.reconcileCheckpointBackupForPod(ctx, statefulMigration, &pod, cluster); err != nil {
-				log.Error(err, "Failed to reconcile CheckpointBackup for pod", "pod", pod.Name, "cluster", cluster)
-				return ctrl.Result{}, err
+	// Step 5: Run the pre-flight checks of the source and target clusters
+	compatible, err := r.reconcilePreflight(ctx, statefulMigration)
+	if err != nil {
+		log.Error(err, "Failed to run pre-flight checks")
+		return ctrl.Result{}, err
+	}
+
+	// Step 6: For each compatible source cluster, create/update CheckpointBackup resources for each pod on the cluster.
+	// A cluster where the pods of some workload could not be listed is left out of the cleanup.
+	reconciledPods := make(map[string][]corev1.Pod)
+	for _, cluster := range clusters {
+		if compatible != nil && !compatible[cluster] {
+			log.Info("Skipping CheckpointBackups on cluster that failed the pre-flight checks", "cluster", cluster)
+			continue
+		}
+		reconciledPods[cluster] = []corev1.Pod{}
+		for i, workload := range workloads {
+			clusterPods, listed := discovered[i][cluster]
+			if !listed {
+				delete(reconciledPods, cluster)
+				break
+			}
+			reconciledPods[cluster] = append(reconciledPods[cluster], clusterPods...)
+			for _, pod := range clusterPods {
+				if err := r.reconcileCheckpointBackupForPod(ctx, statefulMigration, workload, &pod, cluster); err != nil {
+					log.Error(err, "Failed to reconcile CheckpointBackup for pod", "pod", pod.Name, "cluster", cluster)
+					return ctrl.Result{}, err
+				}
 			}
 		}
 	}
 
-	// Step 6: Clean up orphaned CheckpointBackup resources
-	if err := r.cleanupOrphanedCheckpointBackups(ctx, statefulMigration, pods); err != nil {
+	// Step 7: Clean up orphaned CheckpointBackup resources
+	if err := r.cleanupOrphanedCheckpointBackups(ctx, statefulMigration, clusters, reconciledPods); err != nil {
 		log.Error(err, "Failed to cleanup orphaned CheckpointBackup resources")
 		return ctrl.Result{}, err
 	}
 
+	// Step 8: Summarize the CheckpointBackups of each source cluster and evaluate the conditions
+	if err := r.reconcileBackupSummary(ctx, statefulMigration); err != nil {
+		log.Error(err, "Failed to summarize CheckpointBackup resources")
+		return ctrl.Result{}, err
+	}
+	statefulMigration.Status.ObservedGeneration = statefulMigration.Generation
+
 	log.Info("Successfully reconciled StatefulMigration", "name", statefulMigration.Name)
-	return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+	return ctrl.Result{RequeueAfter: r.ResyncPeriod}, nil
+}
+
+// reconcileWorkloads enrolls the workload of the ResourceRef, or the workloads currently matching the WorkloadSelector,
+// and records them in the status. Enrolled workloads get the checkpoint migration label, which is removed from the
+// workloads that stopped matching.
+func (r *MigrationBackupReconciler) reconcileWorkloads(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) ([]migrationv1.ResourceRef, []string, error) {
+	log := logf.FromContext(ctx)
+
+	workloads := []migrationv1.ResourceRef{statefulMigration.Spec.ResourceRef}
+	if statefulMigration.Spec.WorkloadSelector != nil {
+		selected, err := selectWorkloads(ctx, r.Client, statefulMigration)
+		if err != nil {
+			return nil, nil, err
+		}
+		workloads = selected
+
+		for _, previous := range statefulMigration.Status.Workloads {
+			if slices.Contains(workloads, previous) {
+				continue
+			}
+			log.Info("Releasing workload that no longer matches the selector", "kind", previous.Kind, "namespace", previous.Namespace, "name", previous.Name)
+			if err := r.removeLabelFromTargetResource(ctx, statefulMigration, previous); err != nil {
+				return nil, nil, err
+			}
+			r.Recorder.Eventf(statefulMigration, corev1.EventTypeNormal, ReasonWorkloadReleased,
+				"Released %s %s/%s, which no longer matches the workload selector", previous.Kind, previous.Namespace, previous.Name)
+		}
+	}
+
+	// The source clusters are resolved first, since bare pods are labeled on their cluster
+	clusters, err := r.reconcileSourceClusters(ctx, statefulMigration, workloads)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, workload := range workloads {
+		if err := r.addLabelToTargetResource(ctx, statefulMigration, workload); err != nil {
+			return nil, nil, fmt.Errorf("failed to add label to %s %s: %w", workload.Kind, workload.Name, err)
+		}
+	}
+
+	statefulMigration.Status.Workloads = workloads
+	return workloads, clusters, nil
+}
+
+// reconcilePreflight inspects the source clusters and the target cluster whose pre-flight results are
+// missing or outdated, and records the results and the Compatible condition on the StatefulMigration status.
+// The results are shared by the StatefulMigrations using the same clusters, so each cluster is inspected
+// once per preflightInterval. It returns the clusters that passed the checks, or nil if the member clusters
+// cannot be inspected.
+func (r *MigrationBackupReconciler) reconcilePreflight(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) (map[string]bool, error) {
+	if r.MemberClusterClient == nil {
+		return nil, nil
+	}
+
+	clusters := slices.Clone(sourceClusters(statefulMigration))
+	if target := statefulMigration.Spec.TargetCluster; target != "" && !slices.Contains(clusters, target) {
+		clusters = append(clusters, target)
+	}
+
+	now := time.Now()
+	fresh := func(preflight migrationv1.ClusterPreflight) bool {
+		return preflight.CheckTime != nil && now.Sub(preflight.CheckTime.Time) < preflightInterval
+	}
+
+	var results []migrationv1.ClusterPreflight
+	compatible := make(map[string]bool)
+	for _, cluster := range clusters {
+		index := slices.IndexFunc(statefulMigration.Status.Preflight, func(preflight migrationv1.ClusterPreflight) bool {
+			return preflight.Name == cluster
+		})
+
+		var preflight migrationv1.ClusterPreflight
+		if cached, found := r.preflights.Load(cluster); found && fresh(cached.(migrationv1.ClusterPreflight)) {
+			preflight = cached.(migrationv1.ClusterPreflight)
+		} else if index >= 0 && fresh(statefulMigration.Status.Preflight[index]) {
+			// Results recorded before the operator restarted are reused by the other StatefulMigrations too
+			preflight = statefulMigration.Status.Preflight[index]
+			r.preflights.Store(cluster, preflight)
+		} else {
+			preflight = r.inspectCluster(ctx, cluster, now)
+			r.preflights.Store(cluster, preflight)
+		}
+
+		results = append(results, *preflight.DeepCopy())
+		compatible[cluster] = preflight.Compatible
+	}
+
+	statefulMigration.Status.Preflight = results
+	meta.SetStatusCondition(&statefulMigration.Status.Conditions, preflightCondition(statefulMigration))
+
+	return compatible, nil
+}
+
+// inspectCluster runs the pre-flight checks of a member cluster and evaluates whether it can take and restore checkpoints
+func (r *MigrationBackupReconciler) inspectCluster(ctx context.Context, cluster string, now time.Time) migrationv1.ClusterPreflight {
+	log := logf.FromContext(ctx)
+
+	preflight, err := inspectCluster(ctx, r.MemberClusterClient, cluster, now)
+	if err != nil {
+		log.Error(err, "Failed to inspect cluster", "cluster", cluster)
+		checkTime := metav1.NewTime(now)
+		preflight = &migrationv1.ClusterPreflight{
+			Name:      cluster,
+			CheckTime: &checkTime,
+			Message:   fmt.Sprintf("failed to inspect cluster: %v", err),
+		}
+	} else if problems := clusterProblems(preflight); len(problems) > 0 {
+		preflight.Message = strings.Join(problems, "; ")
+	} else {
+		preflight.Compatible = true
+		if len(preflight.UnreachableNodes) > 0 {
+			preflight.Message = fmt.Sprintf("kubelet feature gate %s is unknown on unreachable node(s) %s",
+				containerCheckpointFeatureGate, strings.Join(preflight.UnreachableNodes, ", "))
+		}
+	}
+
+	log.Info("Ran pre-flight checks", "cluster", cluster, "compatible", preflight.Compatible, "message", preflight.Message)
+	return *preflight
+}
+
+// reconcileBackupSummary records the CheckpointBackup of each pod on each source cluster in the StatefulMigration
+// status, with the status reported by the agent on the cluster, and evaluates the Ready, Degraded and Progressing conditions
+func (r *MigrationBackupReconciler) reconcileBackupSummary(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) error {
+	log := logf.FromContext(ctx)
+
+	var backupList migrationv1.CheckpointBackupList
+	if err := r.List(ctx, &backupList, &client.ListOptions{
+		Namespace: statefulMigration.Namespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			"stateful-migration": statefulMigration.Name,
+		}),
+	}); err != nil {
+		return err
+	}
+
+	backupsByCluster := make(map[string][]migrationv1.PodBackupStatus)
+	for i := range backupList.Items {
+		backup := &backupList.Items[i]
+		if backup.GetDeletionTimestamp() != nil {
+			continue
+		}
+		cluster := backup.Labels["target-cluster"]
+
+		// The copy on Karmada carries no status, the agent on the member cluster reports it
+		summary := podBackupStatus(backup)
+		if r.MemberClusterClient != nil {
+			memberBackup, err := r.MemberClusterClient.GetCheckpointBackupFromCluster(ctx, cluster, backup.Namespace, backup.Name)
+			if err != nil {
+				log.V(1).Info("Failed to get CheckpointBackup status from cluster", "backup", backup.Name, "cluster", cluster, "error", err.Error())
+				summary.LastError = fmt.Sprintf("failed to read status from cluster %s: %v", cluster, err)
+			} else {
+				summary = podBackupStatus(memberBackup)
+			}
+		}
+		backupsByCluster[cluster] = append(backupsByCluster[cluster], summary)
+	}
+
+	var clusters []migrationv1.ClusterBackupStatus
+	for _, cluster := range sourceClusters(statefulMigration) {
+		backups := backupsByCluster[cluster]
+		slices.SortFunc(backups, func(a, b migrationv1.PodBackupStatus) int {
+			return cmp.Or(strings.Compare(a.Namespace, b.Namespace), strings.Compare(a.PodName, b.PodName))
+		})
+		clusters = append(clusters, migrationv1.ClusterBackupStatus{Name: cluster, Backups: backups})
+	}
+	statefulMigration.Status.Clusters = clusters
+
+	for _, condition := range backupConditions(statefulMigration) {
+		meta.SetStatusCondition(&statefulMigration.Status.Conditions, condition)
+	}
+	return nil
+}
+
+// migrationNamespace returns the namespace propagated to the source clusters of the StatefulMigration
+func (r *MigrationBackupReconciler) migrationNamespace(statefulMigration *migrationv1.StatefulMigration) string {
+	return cmp.Or(statefulMigration.Spec.MigrationNamespace, r.MigrationNamespace, DefaultMigrationNamespace)
 }
 
-// ensureStatefulMigrationNamespace ensures the stateful-migration namespace exists on Karmada and is propagated to member clusters
+// ensureStatefulMigrationNamespace ensures the migration namespace exists on Karmada and is propagated to member clusters
 func (r *MigrationBackupReconciler) ensureStatefulMigrationNamespace(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) error {
 	log := logf.FromContext(ctx)
-	namespaceName := "stateful-migration"
+	namespaceName := r.migrationNamespace(statefulMigration)
 
 	// Check if namespace exists on Karmada control plane
 	var existingNamespace corev1.Namespace
@@ -191,13 +464,13 @@ func (r *MigrationBackupReconciler) ensureStatefulMigrationNamespace(ctx context
 
 	if errors.IsNotFound(err) {
 		// Create namespace on Karmada control plane
-		log.Info("Creating stateful-migration namespace on Karmada", "namespace", namespaceName)
+		log.Info("Creating migration namespace on Karmada", "namespace", namespaceName)
 
 		namespace := &corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: namespaceName,
 				Labels: map[string]string{
-					"created-by":                "stateful-migration-operator",
+					CreatedByLabel:              CreatedByValue,
 					"app.kubernetes.io/name":    "stateful-migration",
 					"app.kubernetes.io/part-of": "stateful-migration-operator",
 				},
@@ -208,6 +481,7 @@ func (r *MigrationBackupReconciler) ensureStatefulMigrationNamespace(ctx context
 			return fmt.Errorf("failed to create namespace %s on Karmada: %w", namespaceName, err)
 		}
 		log.Info("Successfully created namespace on Karmada", "namespace", namespaceName)
+		r.Recorder.Eventf(statefulMigration, corev1.EventTypeNormal, ReasonNamespaceCreated, "Created namespace %s on Karmada", namespaceName)
 	} else if err != nil {
 		return fmt.Errorf("failed to check namespace %s on Karmada: %w", namespaceName, err)
 	} else {
@@ -216,7 +490,7 @@ func (r *MigrationBackupReconciler) ensureStatefulMigrationNamespace(ctx context
 
 	// Create PropagationPolicy to propagate namespace to member clusters
 	if r.KarmadaClient != nil {
-		if err := r.ensureNamespacePropagationPolicy(ctx, statefulMigration, namespaceName); err != nil {
+		if err := r.reconcileNamespacePropagationPolicies(ctx, statefulMigration); err != nil {
 			return fmt.Errorf("failed to ensure namespace propagation policy: %w", err)
 		}
 	}
@@ -224,57 +498,132 @@ func (r *MigrationBackupReconciler) ensureStatefulMigrationNamespace(ctx context
 	return nil
 }
 
-// ensureNamespacePropagationPolicy ensures PropagationPolicy exists for the stateful-migration namespace
-func (r *MigrationBackupReconciler) ensureNamespacePropagationPolicy(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, namespaceName string) error {
+// namespaceClusters returns, for each migration namespace, the union of the source clusters of the StatefulMigrations
+// using it, leaving out the StatefulMigrations being deleted
+func (r *MigrationBackupReconciler) namespaceClusters(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) (map[string][]string, error) {
+	var statefulMigrations migrationv1.StatefulMigrationList
+	if err := r.List(ctx, &statefulMigrations); err != nil {
+		return nil, fmt.Errorf("failed to list StatefulMigrations: %w", err)
+	}
+
+	clusters := make(map[string][]string)
+	add := func(other *migrationv1.StatefulMigration) {
+		if other.DeletionTimestamp == nil {
+			namespaceName := r.migrationNamespace(other)
+			clusters[namespaceName] = append(clusters[namespaceName], sourceClusters(other)...)
+		}
+	}
+	for i := range statefulMigrations.Items {
+		// The StatefulMigration being reconciled is added as is, the list may not have caught up with it yet
+		if client.ObjectKeyFromObject(&statefulMigrations.Items[i]) != client.ObjectKeyFromObject(statefulMigration) {
+			add(&statefulMigrations.Items[i])
+		}
+	}
+	add(statefulMigration)
+
+	for namespaceName := range clusters {
+		slices.Sort(clusters[namespaceName])
+		clusters[namespaceName] = slices.Compact(clusters[namespaceName])
+	}
+	return clusters, nil
+}
+
+// reconcileNamespacePropagationPolicies propagates the migration namespace of the StatefulMigration to the source
+// clusters of every StatefulMigration using it, and updates the policies of the other migration namespaces, whose
+// StatefulMigrations may have changed namespace or been deleted
+func (r *MigrationBackupReconciler) reconcileNamespacePropagationPolicies(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) error {
+	clusters, err := r.namespaceClusters(ctx, statefulMigration)
+	if err != nil {
+		return err
+	}
+
+	var policies karmadav1alpha1.PropagationPolicyList
+	if err := r.KarmadaClient.List(ctx, &policies, client.MatchingLabels{
+		CreatedByLabel:    CreatedByValue,
+		ResourceTypeLabel: resourceTypeNamespace,
+	}); err != nil {
+		return fmt.Errorf("failed to list namespace PropagationPolicies: %w", err)
+	}
+
+	namespaces := []string{r.migrationNamespace(statefulMigration)}
+	for _, policy := range policies.Items {
+		if !slices.Contains(namespaces, policy.Namespace) {
+			namespaces = append(namespaces, policy.Namespace)
+		}
+	}
+	for _, namespaceName := range namespaces {
+		if err := r.ensureNamespacePropagationPolicy(ctx, statefulMigration, namespaceName, clusters[namespaceName]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureNamespacePropagationPolicy propagates the migration namespace to the given clusters. The policy is deleted
+// once no StatefulMigration uses the namespace, since a policy without cluster names would propagate it everywhere.
+func (r *MigrationBackupReconciler) ensureNamespacePropagationPolicy(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, namespaceName string, clusters []string) error {
 	log := logf.FromContext(ctx)
 	policyName := namespaceName + "-propagation"
 
-	// Check if PropagationPolicy already exists
-	existingPolicy := &karmadav1alpha1.PropagationPolicy{}
-	err := r.KarmadaClient.Get(ctx, types.NamespacedName{
-		Name:      policyName,
-		Namespace: namespaceName,
-	}, existingPolicy)
-
-	if errors.IsNotFound(err) {
-		// Create PropagationPolicy for namespace
-		log.Info("Creating PropagationPolicy for namespace", "policy", policyName, "namespace", namespaceName)
+	if len(clusters) == 0 {
+		policy := &karmadav1alpha1.PropagationPolicy{ObjectMeta: metav1.ObjectMeta{Name: policyName, Namespace: namespaceName}}
+		if err := r.KarmadaClient.DeletePropagationPolicy(ctx, policy); err != nil {
+			return fmt.Errorf("failed to delete namespace PropagationPolicy: %w", err)
+		}
+		return nil
+	}
 
-		policy := &karmadav1alpha1.PropagationPolicy{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      policyName,
-				Namespace: namespaceName,
-				Labels: map[string]string{
-					"created-by":                "stateful-migration-operator",
-					"app.kubernetes.io/name":    "stateful-migration",
-					"app.kubernetes.io/part-of": "stateful-migration-operator",
-					"resource-type":             "namespace",
-				},
+	policy := &karmadav1alpha1.PropagationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      policyName,
+			Namespace: namespaceName,
+			Labels: map[string]string{
+				CreatedByLabel:              CreatedByValue,
+				"app.kubernetes.io/name":    "stateful-migration",
+				"app.kubernetes.io/part-of": "stateful-migration-operator",
+				ResourceTypeLabel:           resourceTypeNamespace,
 			},
-			Spec: karmadav1alpha1.PropagationSpec{
-				ResourceSelectors: []karmadav1alpha1.ResourceSelector{
-					{
-						APIVersion: "v1",
-						Kind:       "Namespace",
-						Name:       namespaceName,
-					},
+		},
+		Spec: karmadav1alpha1.PropagationSpec{
+			ResourceSelectors: []karmadav1alpha1.ResourceSelector{
+				{
+					APIVersion: "v1",
+					Kind:       "Namespace",
+					Name:       namespaceName,
 				},
-				Placement: karmadav1alpha1.Placement{
-					ClusterAffinity: &karmadav1alpha1.ClusterAffinity{
-						ClusterNames: statefulMigration.Spec.SourceClusters,
-					},
+			},
+			Placement: karmadav1alpha1.Placement{
+				ClusterAffinity: &karmadav1alpha1.ClusterAffinity{
+					ClusterNames: clusters,
 				},
 			},
-		}
+		},
+	}
 
-		if err := r.KarmadaClient.CreateOrUpdatePropagationPolicy(ctx, policy); err != nil {
-			return fmt.Errorf("failed to create namespace PropagationPolicy: %w", err)
-		}
-		log.Info("Successfully created PropagationPolicy for namespace", "policy", policyName)
-	} else if err != nil {
+	// Only write the policy when its placement changed, so that every reconcile does not bump it
+	existingPolicy := &karmadav1alpha1.PropagationPolicy{}
+	err := r.KarmadaClient.Get(ctx, client.ObjectKeyFromObject(policy), existingPolicy)
+	if err != nil && !errors.IsNotFound(err) {
 		return fmt.Errorf("failed to check PropagationPolicy %s: %w", policyName, err)
-	} else {
-		log.Info("PropagationPolicy already exists for namespace", "policy", policyName, "namespace", namespaceName)
+	}
+	if err == nil && existingPolicy.Spec.Placement.ClusterAffinity != nil &&
+		slices.Equal(existingPolicy.Spec.Placement.ClusterAffinity.ClusterNames, clusters) {
+		log.V(1).Info("PropagationPolicy for namespace is up to date", "policy", policyName, "namespace", namespaceName)
+		return nil
+	}
+
+	result, err := r.KarmadaClient.CreateOrUpdatePropagationPolicy(ctx, policy)
+	if err != nil {
+		return fmt.Errorf("failed to create or update namespace PropagationPolicy: %w", err)
+	}
+	log.Info("Propagated migration namespace", "policy", policyName, "namespace", namespaceName, "clusters", clusters, "result", result)
+	switch result {
+	case controllerutil.OperationResultCreated:
+		r.Recorder.Eventf(statefulMigration, corev1.EventTypeNormal, ReasonPropagationPolicyCreated,
+			"Created PropagationPolicy %s/%s to propagate namespace %s to clusters %s", namespaceName, policyName, namespaceName, strings.Join(clusters, ", "))
+	case controllerutil.OperationResultUpdated:
+		r.Recorder.Eventf(statefulMigration, corev1.EventTypeNormal, ReasonPropagationPolicyUpdated,
+			"Updated PropagationPolicy %s/%s to propagate namespace %s to clusters %s", namespaceName, policyName, namespaceName, strings.Join(clusters, ", "))
 	}
 
 	return nil
@@ -284,10 +633,12 @@ func (r *MigrationBackupReconciler) ensureNamespacePropagationPolicy(ctx context
 func (r *MigrationBackupReconciler) reconcileDelete(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
-	// Remove label from target resource
-	if err := r.removeLabelFromTargetResource(ctx, statefulMigration); err != nil {
-		log.Error(err, "Failed to remove label from target resource")
-		return ctrl.Result{}, err
+	// Remove label from the enrolled workloads
+	for _, workload := range enrolledWorkloads(statefulMigration) {
+		if err := r.removeLabelFromTargetResource(ctx, statefulMigration, workload); err != nil {
+			log.Error(err, "Failed to remove label from target resource", "workload", workload.Name)
+			return ctrl.Result{}, err
+		}
 	}
 
 	// Delete all related CheckpointBackup resources
@@ -296,6 +647,16 @@ func (r *MigrationBackupReconciler) reconcileDelete(ctx context.Context, statefu
 		return ctrl.Result{}, err
 	}
 
+	// Stop propagating the migration namespace to the clusters no other StatefulMigration backs up
+	if r.KarmadaClient != nil {
+		if err := r.reconcileNamespacePropagationPolicies(ctx, statefulMigration); err != nil {
+			log.Error(err, "Failed to update namespace propagation policy")
+			return ctrl.Result{}, err
+		}
+	}
+
+	r.podWatcher.watch(client.ObjectKeyFromObject(statefulMigration), nil, nil)
+
 	// Remove finalizer
 	controllerutil.RemoveFinalizer(statefulMigration, MigrationBackupFinalizer)
 	if err := r.Update(ctx, statefulMigration); err != nil {
@@ -307,44 +668,20 @@ func (r *MigrationBackupReconciler) reconcileDelete(ctx context.Context, statefu
 	return ctrl.Result{}, nil
 }
 
-// addLabelToTargetResource adds the checkpoint migration label to the target resource
-func (r *MigrationBackupReconciler) addLabelToTargetResource(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) error {
-	resourceRef := statefulMigration.Spec.ResourceRef
-
-	switch strings.ToLower(resourceRef.Kind) {
-	case "statefulset":
-		var sts appsv1.StatefulSet
-		if err := r.Get(ctx, types.NamespacedName{
-			Name:      resourceRef.Name,
-			Namespace: resourceRef.Namespace,
-		}, &sts); err != nil {
-			return err
-		}
-
-		if sts.Labels == nil {
-			sts.Labels = make(map[string]string)
-		}
-		sts.Labels[CheckpointMigrationLabel] = "true"
-
-		return r.Update(ctx, &sts)
-
-	case "deployment":
-		var deployment appsv1.Deployment
-		if err := r.Get(ctx, types.NamespacedName{
-			Name:      resourceRef.Name,
-			Namespace: resourceRef.Namespace,
-		}, &deployment); err != nil {
-			return err
-		}
-
-		if deployment.Labels == nil {
-			deployment.Labels = make(map[string]string)
-		}
-		deployment.Labels[CheckpointMigrationLabel] = "true"
-
-		return r.Update(ctx, &deployment)
+// addLabelToTargetResource marks an enrolled workload with the checkpoint migration label, or annotation, if it is missing.
+// The marker is set with a merge patch under the operator's field manager rather than an update, so that it neither
+// conflicts with nor overwrites concurrent changes of GitOps tools and other controllers.
+func (r *MigrationBackupReconciler) addLabelToTargetResource(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, resourceRef migrationv1.ResourceRef) error {
+	marker, err := ParseWorkloadMarker(r.WorkloadMarker)
+	if err != nil {
+		return err
+	}
+	patch, err := workloadMarkerPatch(marker, true)
+	if err != nil {
+		return err
+	}
 
-	case "pod":
+	if isPodResource(resourceRef) {
 		// For pods, we need to access them on the member clusters, not the management cluster
 		if r.MemberClusterClient == nil {
 			return fmt.Errorf("member cluster client not initialized")
@@ -352,177 +689,194 @@ func (r *MigrationBackupReconciler) addLabelToTargetResource(ctx context.Context
 
 		// Get the pod from the first source cluster (assuming single cluster for pod resource)
 		// In practice, a pod can only exist on one cluster at a time
-		if len(statefulMigration.Spec.SourceClusters) == 0 {
+		clusters := sourceClusters(statefulMigration)
+		if len(clusters) == 0 {
 			return fmt.Errorf("no source clusters specified for pod resource")
 		}
 
-		clusterName := statefulMigration.Spec.SourceClusters[0]
+		clusterName := clusters[0]
 		pod, err := r.MemberClusterClient.GetPodFromCluster(ctx, clusterName, resourceRef.Namespace, resourceRef.Name)
 		if err != nil {
 			return fmt.Errorf("failed to get pod from cluster %s: %w", clusterName, err)
 		}
 
-		if pod.Labels == nil {
-			pod.Labels = make(map[string]string)
-		}
-		pod.Labels[CheckpointMigrationLabel] = "true"
-
-		return r.MemberClusterClient.UpdatePodInCluster(ctx, clusterName, pod)
-
-	default:
-		return fmt.Errorf("unsupported resource kind: %s", resourceRef.Kind)
-	}
-}
-
-// removeLabelFromTargetResource removes the checkpoint migration label from the target resource
-func (r *MigrationBackupReconciler) removeLabelFromTargetResource(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) error {
-	resourceRef := statefulMigration.Spec.ResourceRef
-
-	switch strings.ToLower(resourceRef.Kind) {
-	case "statefulset":
-		var sts appsv1.StatefulSet
-		if err := r.Get(ctx, types.NamespacedName{
-			Name:      resourceRef.Name,
-			Namespace: resourceRef.Namespace,
-		}, &sts); err != nil {
-			if errors.IsNotFound(err) {
-				return nil // Resource already deleted
-			}
-			return err
-		}
-
-		if sts.Labels != nil {
-			delete(sts.Labels, CheckpointMigrationLabel)
+		if hasWorkloadMarker(pod, marker) {
+			return nil
 		}
-
-		return r.Update(ctx, &sts)
-
-	case "deployment":
-		var deployment appsv1.Deployment
-		if err := r.Get(ctx, types.NamespacedName{
-			Name:      resourceRef.Name,
-			Namespace: resourceRef.Namespace,
-		}, &deployment); err != nil {
-			if errors.IsNotFound(err) {
-				return nil // Resource already deleted
-			}
+		if err := r.MemberClusterClient.PatchPodInCluster(ctx, clusterName, pod.Namespace, pod.Name, types.MergePatchType, patch); err != nil {
 			return err
 		}
+		r.Recorder.Eventf(statefulMigration, corev1.EventTypeNormal, ReasonWorkloadLabeled,
+			"%s pod %s/%s on cluster %s for checkpoint migration", workloadMarkerVerb(marker), resourceRef.Namespace, resourceRef.Name, clusterName)
+		return nil
+	}
 
-		if deployment.Labels != nil {
-			delete(deployment.Labels, CheckpointMigrationLabel)
-		}
+	workload, err := getWorkload(ctx, r.Client, resourceRef)
+	if err != nil {
+		return err
+	}
 
-		return r.Update(ctx, &deployment)
+	if hasWorkloadMarker(workload, marker) {
+		return nil
+	}
+	if err := r.Patch(ctx, workload, client.RawPatch(types.MergePatchType, patch), client.FieldOwner(FieldManager)); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(statefulMigration, corev1.EventTypeNormal, ReasonWorkloadLabeled,
+		"%s %s %s/%s for checkpoint migration", workloadMarkerVerb(marker), resourceRef.Kind, resourceRef.Namespace, resourceRef.Name)
+	return nil
+}
 
-	case "pod":
+// removeLabelFromTargetResource removes the checkpoint migration label and annotation from a workload
+func (r *MigrationBackupReconciler) removeLabelFromTargetResource(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, resourceRef migrationv1.ResourceRef) error {
+	if isPodResource(resourceRef) {
 		// For pods, we need to access them on the member clusters, not the management cluster
 		if r.MemberClusterClient == nil {
 			return nil // Skip if member cluster client not available
 		}
 
-		// Try to remove label from pod on each source cluster
-		for _, clusterName := range statefulMigration.Spec.SourceClusters {
-			pod, err := r.MemberClusterClient.GetPodFromCluster(ctx, clusterName, resourceRef.Namespace, resourceRef.Name)
-			if err != nil {
-				if errors.IsNotFound(err) {
-					continue // Pod not found on this cluster, skip
-				}
-				return fmt.Errorf("failed to get pod from cluster %s: %w", clusterName, err)
-			}
-
-			if pod.Labels != nil {
-				delete(pod.Labels, CheckpointMigrationLabel)
-			}
-
-			if err := r.MemberClusterClient.UpdatePodInCluster(ctx, clusterName, pod); err != nil {
-				return fmt.Errorf("failed to update pod on cluster %s: %w", clusterName, err)
+		// Try to remove the marker from the pod on each source cluster
+		for _, clusterName := range sourceClusters(statefulMigration) {
+			if err := r.removeMarkerFromMemberPod(ctx, clusterName, resourceRef.Namespace, resourceRef.Name); err != nil {
+				return fmt.Errorf("failed to remove marker from pod on cluster %s: %w", clusterName, err)
 			}
 		}
 
 		return nil
-
-	default:
-		return fmt.Errorf("unsupported resource kind: %s", resourceRef.Kind)
 	}
-}
 
-// getPodsFromResourceRef gets all pods related to the resource reference
-func (r *MigrationBackupReconciler) getPodsFromResourceRef(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) ([]corev1.Pod, error) {
-	resourceRef := statefulMigration.Spec.ResourceRef
+	patch, err := workloadMarkerPatch(r.WorkloadMarker, false)
+	if err != nil {
+		return err
+	}
 
-	switch strings.ToLower(resourceRef.Kind) {
-	case "statefulset":
-		var sts appsv1.StatefulSet
-		if err := r.Get(ctx, types.NamespacedName{
-			Name:      resourceRef.Name,
-			Namespace: resourceRef.Namespace,
-		}, &sts); err != nil {
-			return nil, err
+	workload, err := getWorkload(ctx, r.Client, resourceRef)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil // Resource already deleted
 		}
+		return err
+	}
 
-		return r.getPodsFromSelector(ctx, resourceRef.Namespace, sts.Spec.Selector)
-
-	case "deployment":
-		var deployment appsv1.Deployment
-		if err := r.Get(ctx, types.NamespacedName{
-			Name:      resourceRef.Name,
-			Namespace: resourceRef.Namespace,
-		}, &deployment); err != nil {
-			return nil, err
-		}
+	if !hasAnyWorkloadMarker(workload) {
+		return nil
+	}
+	return client.IgnoreNotFound(r.Patch(ctx, workload, client.RawPatch(types.MergePatchType, patch), client.FieldOwner(FieldManager)))
+}
 
-		return r.getPodsFromSelector(ctx, resourceRef.Namespace, deployment.Spec.Selector)
+// getPodsFromResourceRef gets the pods of the resource reference on each source cluster. The workload is read
+// from Karmada, but its pods only exist on the member clusters it is propagated to. Clusters whose pods cannot
+// be listed are left out of the result, so that their CheckpointBackups are kept as they are.
+func (r *MigrationBackupReconciler) getPodsFromResourceRef(ctx context.Context, resourceRef migrationv1.ResourceRef, clusters []string) (map[string][]corev1.Pod, error) {
+	log := logf.FromContext(ctx)
 
-	case "pod":
-		// For pods, we need to access them on the member clusters, not the management cluster
-		if r.MemberClusterClient == nil {
-			return nil, fmt.Errorf("member cluster client not initialized")
-		}
+	if r.MemberClusterClient == nil {
+		return nil, fmt.Errorf("member cluster client not initialized")
+	}
 
-		var allPods []corev1.Pod
+	if isPodResource(resourceRef) {
+		pods := make(map[string][]corev1.Pod)
 
 		// Get pod from each source cluster
-		for _, clusterName := range statefulMigration.Spec.SourceClusters {
+		for _, clusterName := range clusters {
 			pod, err := r.MemberClusterClient.GetPodFromCluster(ctx, clusterName, resourceRef.Namespace, resourceRef.Name)
 			if err != nil {
 				if errors.IsNotFound(err) {
-					continue // Pod not found on this cluster, skip
+					pods[clusterName] = nil // Pod not found on this cluster
+					continue
 				}
-				return nil, fmt.Errorf("failed to get pod from cluster %s: %w", clusterName, err)
+				log.Error(err, "Failed to get pod from cluster", "cluster", clusterName)
+				continue
 			}
-			allPods = append(allPods, *pod)
+			pods[clusterName] = []corev1.Pod{*pod}
 		}
 
-		return allPods, nil
-
-	default:
-		return nil, fmt.Errorf("unsupported resource kind: %s", resourceRef.Kind)
+		return pods, nil
 	}
-}
 
-// getPodsFromSelector gets pods matching the given selector
-func (r *MigrationBackupReconciler) getPodsFromSelector(ctx context.Context, namespace string, selector *metav1.LabelSelector) ([]corev1.Pod, error) {
-	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	workload, err := getWorkload(ctx, r.Client, resourceRef)
 	if err != nil {
 		return nil, err
 	}
-
-	var podList corev1.PodList
-	if err := r.List(ctx, &podList, &client.ListOptions{
-		Namespace:     namespace,
-		LabelSelector: labelSelector,
-	}); err != nil {
+	selector, err := workloadSelector(ctx, r.Client, workload)
+	if err != nil {
 		return nil, err
 	}
+	return r.getWorkloadPods(ctx, clusters, workload, selector)
+}
+
+// getWorkloadPods lists the pods matching the workload's selector on each cluster, keeping those whose
+// controller owner references lead to the workload
+func (r *MigrationBackupReconciler) getWorkloadPods(ctx context.Context, clusters []string, workload *unstructured.Unstructured, selector labels.Selector) (map[string][]corev1.Pod, error) {
+	log := logf.FromContext(ctx)
+
+	pods := make(map[string][]corev1.Pod)
+	for _, cluster := range clusters {
+		podList, err := r.MemberClusterClient.ListPodsFromCluster(ctx, cluster, workload.GetNamespace(), selector.String())
+		if err != nil {
+			log.Error(err, "Failed to list pods from cluster", "cluster", cluster)
+			continue
+		}
+
+		// Intermediate owners, such as the ReplicaSets of a Deployment, are shared by many pods
+		owners := make(map[types.UID]*unstructured.Unstructured)
+		getOwner := func(ref *metav1.OwnerReference) (*unstructured.Unstructured, error) {
+			if owner, cached := owners[ref.UID]; cached {
+				return owner, nil
+			}
+			gv, err := schema.ParseGroupVersion(ref.APIVersion)
+			if err != nil {
+				return nil, err
+			}
+			mapping, err := r.RESTMapper().RESTMapping(gv.WithKind(ref.Kind).GroupKind(), gv.Version)
+			if err != nil {
+				return nil, err
+			}
+			owner, err := r.MemberClusterClient.GetObjectFromCluster(ctx, cluster, mapping, workload.GetNamespace(), ref.Name)
+			if errors.IsNotFound(err) {
+				owner, err = nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			owners[ref.UID] = owner
+			return owner, nil
+		}
 
-	return podList.Items, nil
+		clusterPods := []corev1.Pod{}
+		listed := true
+		for i := range podList.Items {
+			owned, err := isOwnedByWorkload(&podList.Items[i], workload, getOwner)
+			if err != nil {
+				log.Error(err, "Failed to resolve the owner of pod", "pod", podList.Items[i].Name, "cluster", cluster)
+				listed = false
+				break
+			}
+			if owned {
+				clusterPods = append(clusterPods, podList.Items[i])
+			}
+		}
+		if listed {
+			pods[cluster] = clusterPods
+		}
+	}
+
+	return pods, nil
+}
+
+// checkpointBackupName names the CheckpointBackup of a pod on a cluster. The pod's namespace is part of the name
+// when the StatefulMigration enrolls workloads from several namespaces, whose pods may share names.
+func checkpointBackupName(statefulMigration *migrationv1.StatefulMigration, pod *corev1.Pod, cluster string) string {
+	if selector := statefulMigration.Spec.WorkloadSelector; selector != nil && selector.NamespaceSelector != nil {
+		return fmt.Sprintf("%s-%s-%s-%s", statefulMigration.Name, pod.Namespace, pod.Name, cluster)
+	}
+	return fmt.Sprintf("%s-%s-%s", statefulMigration.Name, pod.Name, cluster)
 }
 
-// reconcileCheckpointBackupForPod creates or updates a CheckpointBackup for a specific pod and cluster
-func (r *MigrationBackupReconciler) reconcileCheckpointBackupForPod(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, pod *corev1.Pod, cluster string) error {
+// reconcileCheckpointBackupForPod creates or updates a CheckpointBackup for a specific pod of a workload and cluster
+func (r *MigrationBackupReconciler) reconcileCheckpointBackupForPod(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, workload migrationv1.ResourceRef, pod *corev1.Pod, cluster string) error {
 	// Generate CheckpointBackup name
-	backupName := fmt.Sprintf("%s-%s-%s", statefulMigration.Name, pod.Name, cluster)
+	backupName := checkpointBackupName(statefulMigration, pod, cluster)
 
 	// Create CheckpointBackup spec
 	backup := &migrationv1.CheckpointBackup{
@@ -541,9 +895,12 @@ func (r *MigrationBackupReconciler) reconcileCheckpointBackupForPod(ctx context.
 				Namespace: pod.Namespace,
 				Name:      pod.Name,
 			},
-			ResourceRef: statefulMigration.Spec.ResourceRef,
-			Registry:    statefulMigration.Spec.Registry,
-			Containers:  r.extractContainerInfo(pod),
+			ResourceRef:             workload,
+			Registry:                statefulMigration.Spec.Registry,
+			Containers:              r.extractContainerInfo(statefulMigration, pod),
+			StartingDeadlineSeconds: statefulMigration.Spec.StartingDeadlineSeconds,
+			ConcurrencyPolicy:       statefulMigration.Spec.ConcurrencyPolicy,
+			Suspend:                 statefulMigration.Spec.Suspend,
 		},
 	}
 
@@ -560,6 +917,8 @@ func (r *MigrationBackupReconciler) reconcileCheckpointBackupForPod(ctx context.
 			if err := r.Create(ctx, backup); err != nil {
 				return err
 			}
+			r.Recorder.Eventf(statefulMigration, corev1.EventTypeNormal, ReasonBackupCreated,
+				"Created CheckpointBackup %s for pod %s/%s on cluster %s", backupName, pod.Namespace, pod.Name, cluster)
 		} else {
 			return err
 		}
@@ -569,28 +928,58 @@ func (r *MigrationBackupReconciler) reconcileCheckpointBackupForPod(ctx context.
 		if err := r.Update(ctx, &existingBackup); err != nil {
 			return err
 		}
+		backup = &existingBackup
 	}
 
 	// Create Karmada PropagationPolicy to distribute CheckpointBackup to target cluster
-	return r.createOrUpdatePropagationPolicy(ctx, backup, cluster)
+	return r.createOrUpdatePropagationPolicy(ctx, statefulMigration, backup, cluster)
 }
 
-// extractContainerInfo extracts container information from a pod
-func (r *MigrationBackupReconciler) extractContainerInfo(pod *corev1.Pod) []migrationv1.Container {
+// extractContainerInfo lists the containers of a pod with the strategy the container rules of the
+// StatefulMigration give them. Native sidecars are listed like regular containers; init containers
+// that run to completion are only listed when they are skipped, as they always run again otherwise.
+func (r *MigrationBackupReconciler) extractContainerInfo(statefulMigration *migrationv1.StatefulMigration, pod *corev1.Pod) []migrationv1.Container {
 	var containers []migrationv1.Container
 
+	for _, container := range pod.Spec.InitContainers {
+		strategy := containerStrategy(statefulMigration.Spec.ContainerRules, container.Name)
+		isSidecar := container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways
+		if !isSidecar && strategy != migrationv1.ContainerStrategySkip {
+			continue
+		}
+		containers = append(containers, migrationv1.Container{
+			Name:     container.Name,
+			Image:    container.Image,
+			Strategy: strategy,
+		})
+	}
+
 	for _, container := range pod.Spec.Containers {
 		containers = append(containers, migrationv1.Container{
-			Name:  container.Name,
-			Image: container.Image,
+			Name:     container.Name,
+			Image:    container.Image,
+			Strategy: containerStrategy(statefulMigration.Spec.ContainerRules, container.Name),
 		})
 	}
 
 	return containers
 }
 
+// containerStrategy returns the strategy of the first rule matching the container name, or Checkpoint if none does
+func containerStrategy(rules []migrationv1.ContainerRule, name string) migrationv1.ContainerStrategy {
+	for _, rule := range rules {
+		for _, pattern := range rule.Names {
+			// A malformed pattern matches nothing
+			if matched, _ := path.Match(pattern, name); matched {
+				return cmp.Or(rule.Strategy, migrationv1.ContainerStrategyCheckpoint)
+			}
+		}
+	}
+	return migrationv1.ContainerStrategyCheckpoint
+}
+
 // createOrUpdatePropagationPolicy creates or updates a Karmada PropagationPolicy for the CheckpointBackup
-func (r *MigrationBackupReconciler) createOrUpdatePropagationPolicy(ctx context.Context, backup *migrationv1.CheckpointBackup, cluster string) error {
+func (r *MigrationBackupReconciler) createOrUpdatePropagationPolicy(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, backup *migrationv1.CheckpointBackup, cluster string) error {
 	log := logf.FromContext(ctx)
 
 	// Skip if Karmada client is not available
@@ -599,34 +988,36 @@ func (r *MigrationBackupReconciler) createOrUpdatePropagationPolicy(ctx context.
 		return nil
 	}
 
-	policyName := fmt.Sprintf("%s-policy", backup.Name)
-
-	policy := &karmadav1alpha1.PropagationPolicy{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      policyName,
-			Namespace: backup.Namespace,
-		},
-		Spec: karmadav1alpha1.PropagationSpec{
-			ResourceSelectors: []karmadav1alpha1.ResourceSelector{
-				{
-					APIVersion: "migration.dcnlab.com/v1",
-					Kind:       "CheckpointBackup",
-					Name:       backup.Name,
-				},
-			},
-			Placement: karmadav1alpha1.Placement{
-				ClusterAffinity: &karmadav1alpha1.ClusterAffinity{
-					ClusterNames: []string{cluster},
-				},
-			},
-		},
+	// The checkpoint agent on the member cluster needs the registry credentials to push checkpoint images
+	var extraSelectors []karmadav1alpha1.ResourceSelector
+	if backup.Spec.Registry.SecretRef != nil {
+		extraSelectors = append(extraSelectors, karmadav1alpha1.ResourceSelector{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Name:       backup.Spec.Registry.SecretRef.Name,
+		})
 	}
 
-	return r.KarmadaClient.CreateOrUpdatePropagationPolicy(ctx, policy)
+	// The policy is owned by the CheckpointBackup and deleted with it
+	policy, err := checkpointPropagationPolicy(backup, r.Scheme, resourceTypeCheckpointBackup, cluster, extraSelectors...)
+	if err != nil {
+		return err
+	}
+	result, err := r.KarmadaClient.CreateOrUpdatePropagationPolicy(ctx, policy)
+	if err != nil {
+		return err
+	}
+	if result == controllerutil.OperationResultCreated {
+		r.Recorder.Eventf(statefulMigration, corev1.EventTypeNormal, ReasonPropagationPolicyCreated,
+			"Created PropagationPolicy %s to propagate CheckpointBackup %s to cluster %s", policy.Name, backup.Name, cluster)
+	}
+	return nil
 }
 
-// cleanupOrphanedCheckpointBackups removes CheckpointBackup resources that no longer have corresponding pods
-func (r *MigrationBackupReconciler) cleanupOrphanedCheckpointBackups(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, currentPods []corev1.Pod) error {
+// cleanupOrphanedCheckpointBackups removes CheckpointBackup resources whose pods no longer run on their cluster,
+// including the pods of workloads that are no longer enrolled, and tears down the CheckpointBackups of the clusters
+// that are no longer source clusters. Of the source clusters, only the backups of the clusters in currentPods are considered.
+func (r *MigrationBackupReconciler) cleanupOrphanedCheckpointBackups(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, clusters []string, currentPods map[string][]corev1.Pod) error {
 	// Get all CheckpointBackup resources owned by this StatefulMigration
 	var backupList migrationv1.CheckpointBackupList
 	if err := r.List(ctx, &backupList, &client.ListOptions{
@@ -638,25 +1029,154 @@ func (r *MigrationBackupReconciler) cleanupOrphanedCheckpointBackups(ctx context
 		return err
 	}
 
-	// Create a set of current pod names for quick lookup
-	currentPodNames := make(map[string]bool)
-	for _, pod := range currentPods {
-		currentPodNames[pod.Name] = true
+	// Create a set of current pods of each cluster for quick lookup
+	currentPodKeys := make(map[string]map[types.NamespacedName]bool)
+	for cluster, pods := range currentPods {
+		currentPodKeys[cluster] = make(map[types.NamespacedName]bool)
+		for _, pod := range pods {
+			currentPodKeys[cluster][client.ObjectKeyFromObject(&pod)] = true
+		}
 	}
 
-	// Delete CheckpointBackup resources for pods that no longer exist
+	// The source clusters a migration moves the workloads away from keep their backups until it finishes,
+	// for its final checkpoint and rollback
+	migration := statefulMigration.Status.Migration
+	migrating := migration != nil && !isMigrationFinished(migration.Phase)
+
+	// Delete CheckpointBackup resources for pods that no longer exist on their cluster
 	for _, backup := range backupList.Items {
-		podName, exists := backup.Labels["target-pod"]
-		if !exists || !currentPodNames[podName] {
-			if err := r.Delete(ctx, &backup); err != nil && !errors.IsNotFound(err) {
+		if backup.GetDeletionTimestamp() != nil {
+			continue
+		}
+		cluster := backup.Labels["target-cluster"]
+		if !slices.Contains(clusters, cluster) {
+			if migrating {
+				continue
+			}
+			if err := r.releaseRemovedClusterBackup(ctx, statefulMigration, &backup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		podKeys, listed := currentPodKeys[cluster]
+		if !listed {
+			continue
+		}
+		podKey := types.NamespacedName{Namespace: backup.Spec.PodRef.Namespace, Name: backup.Spec.PodRef.Name}
+		if !podKeys[podKey] {
+			if err := r.deleteCheckpointBackup(ctx, &backup); err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
 				return err
 			}
+			r.Recorder.Eventf(statefulMigration, corev1.EventTypeNormal, ReasonBackupDeleted,
+				"Deleted CheckpointBackup %s, pod %s no longer runs on cluster %s", backup.Name, podKey, cluster)
+		}
+	}
+
+	// The checkpoints retained for a cluster that is a source cluster again are superseded by its new backups
+	statefulMigration.Status.RetainedCheckpoints = slices.DeleteFunc(statefulMigration.Status.RetainedCheckpoints,
+		func(retained migrationv1.RetainedCheckpoint) bool { return slices.Contains(clusters, retained.Cluster) })
+
+	return nil
+}
+
+// releaseRemovedClusterBackup tears down a CheckpointBackup of a cluster that is no longer a source cluster: its copy
+// and the marker of its bare pod on the cluster, and the backup and its PropagationPolicy on Karmada. The last
+// checkpoint of the pod is recorded first if the StatefulMigration retains the checkpoints of removed clusters.
+// The cluster may have left Karmada or be unreachable, so the changes on the cluster are made on a best-effort
+// basis; Karmada deletes the copy once the cluster is back.
+func (r *MigrationBackupReconciler) releaseRemovedClusterBackup(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, backup *migrationv1.CheckpointBackup) error {
+	log := logf.FromContext(ctx)
+	cluster := backup.Labels["target-cluster"]
+	podRef := backup.Spec.PodRef
+
+	retained := false
+	if statefulMigration.Spec.RemovedClusterPolicy == migrationv1.RemovedClusterRetain {
+		retained = r.retainCheckpoint(ctx, statefulMigration, backup)
+	}
+
+	if r.MemberClusterClient != nil {
+		if err := r.MemberClusterClient.DeleteCheckpointBackupFromCluster(ctx, cluster, backup.Namespace, backup.Name); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to delete CheckpointBackup from removed source cluster", "backup", backup.Name, "cluster", cluster)
+		}
+		if isPodResource(backup.Spec.ResourceRef) {
+			if err := r.removeMarkerFromMemberPod(ctx, cluster, podRef.Namespace, podRef.Name); err != nil {
+				log.Error(err, "Failed to remove marker from pod on removed source cluster", "pod", podRef.Name, "cluster", cluster)
+			}
 		}
 	}
 
+	if err := r.deleteCheckpointBackup(ctx, backup); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	message := fmt.Sprintf("Deleted CheckpointBackup %s of pod %s/%s, cluster %s is no longer a source cluster", backup.Name, podRef.Namespace, podRef.Name, cluster)
+	if retained {
+		message += "; its last checkpoint is retained"
+	}
+	r.Recorder.Event(statefulMigration, corev1.EventTypeNormal, ReasonBackupDeleted, message)
 	return nil
 }
 
+// retainCheckpoint records the last checkpoint of the pod of a CheckpointBackup in the status of the StatefulMigration,
+// as reported by the agent on the cluster or, if the cluster cannot be reached, by the backup on Karmada. It reports
+// whether the pod had a checkpoint to retain.
+func (r *MigrationBackupReconciler) retainCheckpoint(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, backup *migrationv1.CheckpointBackup) bool {
+	cluster := backup.Labels["target-cluster"]
+	status := backup.Status
+	if r.MemberClusterClient != nil {
+		if memberBackup, err := r.MemberClusterClient.GetCheckpointBackupFromCluster(ctx, cluster, backup.Namespace, backup.Name); err == nil {
+			status = memberBackup.Status
+		}
+	}
+	if status.LastSuccessfulTime == nil || len(status.Containers) == 0 {
+		return false
+	}
+
+	retained := migrationv1.RetainedCheckpoint{
+		Cluster:     cluster,
+		PodRef:      backup.Spec.PodRef,
+		ResourceRef: backup.Spec.ResourceRef,
+		Time:        *status.LastSuccessfulTime,
+		Containers:  status.Containers,
+	}
+	checkpoints := statefulMigration.Status.RetainedCheckpoints
+	if i := slices.IndexFunc(checkpoints, func(existing migrationv1.RetainedCheckpoint) bool {
+		return existing.Cluster == cluster && existing.PodRef == backup.Spec.PodRef
+	}); i >= 0 {
+		checkpoints[i] = retained
+	} else {
+		statefulMigration.Status.RetainedCheckpoints = append(checkpoints, retained)
+	}
+	return true
+}
+
+// removeMarkerFromMemberPod removes the label or annotation marking an enrolled pod on a member cluster
+func (r *MigrationBackupReconciler) removeMarkerFromMemberPod(ctx context.Context, cluster, namespace, name string) error {
+	pod, err := r.MemberClusterClient.GetPodFromCluster(ctx, cluster, namespace, name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !hasAnyWorkloadMarker(pod) {
+		return nil
+	}
+
+	patch, err := workloadMarkerPatch(r.WorkloadMarker, false)
+	if err != nil {
+		return err
+	}
+	return r.MemberClusterClient.PatchPodInCluster(ctx, cluster, namespace, name, types.MergePatchType, patch)
+}
+
 // deleteAllCheckpointBackups deletes all CheckpointBackup resources owned by the StatefulMigration
 func (r *MigrationBackupReconciler) deleteAllCheckpointBackups(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) error {
 	var backupList migrationv1.CheckpointBackupList
@@ -670,19 +1190,216 @@ func (r *MigrationBackupReconciler) deleteAllCheckpointBackups(ctx context.Conte
 	}
 
 	for _, backup := range backupList.Items {
-		if err := r.Delete(ctx, &backup); err != nil && !errors.IsNotFound(err) {
+		if err := r.deleteCheckpointBackup(ctx, &backup); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
 			return err
 		}
+		r.Recorder.Eventf(statefulMigration, corev1.EventTypeNormal, ReasonBackupDeleted,
+			"Deleted CheckpointBackup %s of pod %s/%s on cluster %s", backup.Name, backup.Spec.PodRef.Namespace, backup.Spec.PodRef.Name, backup.Labels["target-cluster"])
 	}
 
 	return nil
 }
 
+// deleteCheckpointBackup deletes a CheckpointBackup and the PropagationPolicy that propagates it
+func (r *MigrationBackupReconciler) deleteCheckpointBackup(ctx context.Context, backup *migrationv1.CheckpointBackup) error {
+	if err := deleteCheckpointPropagationPolicy(ctx, r.KarmadaClient, backup); err != nil {
+		return err
+	}
+	return r.Delete(ctx, backup)
+}
+
+// workloadIndexValue is the index value of a workload, matching kinds case-insensitively like resourceMapping
+func workloadIndexValue(kind, namespace, name string) string {
+	return strings.ToLower(kind) + "/" + namespace + "/" + name
+}
+
+// indexWorkloads returns the index values of the workloads enrolled by a StatefulMigration
+func indexWorkloads(obj client.Object) []string {
+	statefulMigration, ok := obj.(*migrationv1.StatefulMigration)
+	if !ok {
+		return nil
+	}
+
+	var values []string
+	for _, workload := range enrolledWorkloads(statefulMigration) {
+		if workload.Name == "" {
+			continue
+		}
+		values = append(values, workloadIndexValue(workload.Kind, cmp.Or(workload.Namespace, statefulMigration.Namespace), workload.Name))
+	}
+	return values
+}
+
+// selectsWorkload reports whether the WorkloadSelector of the StatefulMigration matches the workload.
+// namespace is the workload's namespace, only needed if the selector has a namespace selector.
+func selectsWorkload(statefulMigration *migrationv1.StatefulMigration, workload client.Object, namespace *corev1.Namespace) bool {
+	enrollment := statefulMigration.Spec.WorkloadSelector
+	if enrollment == nil {
+		return false
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&enrollment.LabelSelector)
+	if err != nil || !selector.Matches(labels.Set(workload.GetLabels())) {
+		return false
+	}
+	if enrollment.NamespaceSelector == nil {
+		return workload.GetNamespace() == statefulMigration.Namespace
+	}
+	namespaceSelector, err := metav1.LabelSelectorAsSelector(enrollment.NamespaceSelector)
+	return err == nil && namespace != nil && namespaceSelector.Matches(labels.Set(namespace.Labels))
+}
+
+// findMigrationsForWorkload maps a workload on Karmada to the StatefulMigrations that enroll it,
+// or whose WorkloadSelector matches it and so may enroll or release it
+func (r *MigrationBackupReconciler) findMigrationsForWorkload(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := logf.FromContext(ctx)
+
+	gvk, err := r.GroupVersionKindFor(obj)
+	if err != nil {
+		log.Error(err, "Failed to resolve the kind of workload", "name", obj.GetName())
+		return nil
+	}
+
+	var enrolling migrationv1.StatefulMigrationList
+	if err := r.List(ctx, &enrolling, client.MatchingFields{
+		workloadIndexKey: workloadIndexValue(gvk.Kind, obj.GetNamespace(), obj.GetName()),
+	}); err != nil {
+		log.Error(err, "Failed to list StatefulMigrations enrolling workload", "kind", gvk.Kind, "name", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, statefulMigration := range enrolling.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&statefulMigration)})
+	}
+
+	// A workload that starts matching a WorkloadSelector is not indexed until it is enrolled
+	var statefulMigrations migrationv1.StatefulMigrationList
+	if err := r.List(ctx, &statefulMigrations); err != nil {
+		log.Error(err, "Failed to list StatefulMigrations")
+		return requests
+	}
+	var namespace *corev1.Namespace
+	for i := range statefulMigrations.Items {
+		statefulMigration := &statefulMigrations.Items[i]
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(statefulMigration)}
+		if statefulMigration.Spec.WorkloadSelector == nil || slices.Contains(requests, request) {
+			continue
+		}
+		if statefulMigration.Spec.WorkloadSelector.NamespaceSelector != nil && namespace == nil {
+			namespace = &corev1.Namespace{}
+			if err := r.Get(ctx, types.NamespacedName{Name: obj.GetNamespace()}, namespace); err != nil {
+				log.Error(err, "Failed to get namespace of workload", "namespace", obj.GetNamespace())
+				return requests
+			}
+		}
+		if selectsWorkload(statefulMigration, obj, namespace) {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+// watchesMemberPod reports whether a pod on a member cluster may belong to a workload the StatefulMigration backs up there
+func watchesMemberPod(statefulMigration *migrationv1.StatefulMigration, memberPod MemberPod) bool {
+	if !slices.Contains(sourceClusters(statefulMigration), memberPod.Cluster) {
+		return false
+	}
+	return slices.ContainsFunc(enrolledWorkloads(statefulMigration), func(workload migrationv1.ResourceRef) bool {
+		if cmp.Or(workload.Namespace, statefulMigration.Namespace) != memberPod.Pod.Namespace {
+			return false
+		}
+		return !isPodResource(workload) || workload.Name == memberPod.Pod.Name
+	})
+}
+
+// memberPodScopes returns the namespaces of the clusters holding pods of the workloads enrolled by the StatefulMigration
+func memberPodScopes(statefulMigration *migrationv1.StatefulMigration, clusters []string) []memberPodScope {
+	var scopes []memberPodScope
+	for _, cluster := range clusters {
+		for _, workload := range enrolledWorkloads(statefulMigration) {
+			scope := memberPodScope{Cluster: cluster, Namespace: cmp.Or(workload.Namespace, statefulMigration.Namespace)}
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// indexMemberPodScopes returns the index values of the namespaces of the source clusters a StatefulMigration watches pods in
+func indexMemberPodScopes(obj client.Object) []string {
+	statefulMigration, ok := obj.(*migrationv1.StatefulMigration)
+	if !ok {
+		return nil
+	}
+
+	var values []string
+	for _, scope := range memberPodScopes(statefulMigration, sourceClusters(statefulMigration)) {
+		values = append(values, scope.String())
+	}
+	return values
+}
+
+// findMigrationsForMemberPod maps a pod on a member cluster to the StatefulMigrations backing up pods of its namespace there
+func (r *MigrationBackupReconciler) findMigrationsForMemberPod(ctx context.Context, memberPod MemberPod) []reconcile.Request {
+	var statefulMigrations migrationv1.StatefulMigrationList
+	if err := r.List(ctx, &statefulMigrations, client.MatchingFields{
+		memberPodScopeIndexKey: memberPodScope{Cluster: memberPod.Cluster, Namespace: memberPod.Pod.Namespace}.String(),
+	}); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list StatefulMigrations")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range statefulMigrations.Items {
+		if watchesMemberPod(&statefulMigrations.Items[i], memberPod) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&statefulMigrations.Items[i])})
+		}
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
+// Besides its StatefulMigrations and their CheckpointBackups, the controller watches the StatefulSets,
+// Deployments and ResourceBindings on Karmada and the pods on the source clusters, so that backups follow
+// scaling and rescheduling.
 func (r *MigrationBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &migrationv1.StatefulMigration{}, workloadIndexKey, indexWorkloads); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &migrationv1.StatefulMigration{}, memberPodScopeIndexKey, indexMemberPodScopes); err != nil {
+		return err
+	}
+
+	r.podWatcher = newMemberPodWatcher()
+	if err := mgr.Add(r.podWatcher); err != nil {
+		return err
+	}
+
+	// PropagationPolicies orphaned while the operator was down are collected on startup, and Karmada is taught
+	// to collect the status of the CheckpointBackups and CheckpointRestores from the member clusters
+	if r.Karmada != nil {
+		if err := mgr.Add(&propagationPolicyCollector{connection: r.Karmada, reader: mgr.GetAPIReader()}); err != nil {
+			return err
+		}
+		if err := mgr.Add(&resourceInterpreterInstaller{connection: r.Karmada}); err != nil {
+			return err
+		}
+	}
+
+	workloadPredicates := builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}))
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&migrationv1.StatefulMigration{}).
 		Owns(&migrationv1.CheckpointBackup{}).
+		Watches(&appsv1.StatefulSet{}, handler.EnqueueRequestsFromMapFunc(r.findMigrationsForWorkload), workloadPredicates).
+		Watches(&appsv1.Deployment{}, handler.EnqueueRequestsFromMapFunc(r.findMigrationsForWorkload), workloadPredicates).
+		Watches(&workv1alpha2.ResourceBinding{}, handler.EnqueueRequestsFromMapFunc(r.findMigrationsForBinding),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WatchesRawSource(source.Channel(r.podWatcher.events, handler.TypedEnqueueRequestsFromMapFunc(r.findMigrationsForMemberPod))).
 		Named("migrationbackup").
 		Complete(r)
 }
@@ -17,7 +17,20 @@ limitations under the License.
 package controller
 
 import (
+	"context"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
 )
 
 var _ = Describe("MigrationBackup Controller", func() {
@@ -29,4 +42,388 @@ var _ = Describe("MigrationBackup Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When cleaning up orphaned CheckpointBackups", func() {
+		ctx := context.Background()
+
+		newBackup := func(pod, cluster string) *migrationv1.CheckpointBackup {
+			return &migrationv1.CheckpointBackup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cleanup-" + pod + "-" + cluster,
+					Namespace: "default",
+					Labels: map[string]string{
+						"stateful-migration": "cleanup",
+						"target-cluster":     cluster,
+						"target-pod":         pod,
+					},
+				},
+				Spec: migrationv1.CheckpointBackupSpec{
+					Schedule: "*/5 * * * *",
+					PodRef:   migrationv1.PodRef{Namespace: "default", Name: pod},
+					ResourceRef: migrationv1.ResourceRef{
+						APIVersion: "apps/v1",
+						Kind:       "StatefulSet",
+						Namespace:  "default",
+						Name:       "web",
+					},
+					Registry: migrationv1.Registry{URL: "registry.example.com", Repository: "checkpoints"},
+				},
+			}
+		}
+
+		It("should only delete the backups of pods that left a listed cluster", func() {
+			backups := []*migrationv1.CheckpointBackup{
+				newBackup("web-0", "member1"),
+				newBackup("web-1", "member1"),
+				newBackup("web-1", "member2"),
+			}
+			for _, backup := range backups {
+				Expect(k8sClient.Create(ctx, backup)).To(Succeed())
+			}
+			DeferCleanup(func() {
+				for _, backup := range backups {
+					_ = k8sClient.Delete(ctx, backup)
+				}
+			})
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &MigrationBackupReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+			statefulMigration := &migrationv1.StatefulMigration{
+				ObjectMeta: metav1.ObjectMeta{Name: "cleanup", Namespace: "default"},
+			}
+
+			// web-1 moved away from member1, and the pods of member2 could not be listed
+			currentPods := map[string][]corev1.Pod{
+				"member1": {{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default"}}},
+			}
+			Expect(reconciler.cleanupOrphanedCheckpointBackups(ctx, statefulMigration, []string{"member1", "member2"}, currentPods)).To(Succeed())
+
+			exists := func(backup *migrationv1.CheckpointBackup) bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: backup.Name, Namespace: backup.Namespace}, &migrationv1.CheckpointBackup{})
+				if errors.IsNotFound(err) {
+					return false
+				}
+				Expect(err).NotTo(HaveOccurred())
+				return true
+			}
+			Expect(exists(backups[0])).To(BeTrue())
+			Expect(exists(backups[1])).To(BeFalse())
+			Expect(exists(backups[2])).To(BeTrue())
+			Expect(recorder.Events).To(Receive(And(ContainSubstring(ReasonBackupDeleted), ContainSubstring(backups[1].Name))))
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should tear down the backups of removed clusters and retain their checkpoints", func() {
+			backups := []*migrationv1.CheckpointBackup{
+				newBackup("web-0", "member1"),
+				newBackup("web-0", "member3"),
+			}
+			for _, backup := range backups {
+				Expect(k8sClient.Create(ctx, backup)).To(Succeed())
+			}
+			DeferCleanup(func() {
+				for _, backup := range backups {
+					_ = k8sClient.Delete(ctx, backup)
+				}
+			})
+			checkpointTime := metav1.NewTime(metav1.Now().Truncate(time.Second))
+			backups[1].Status.LastSuccessfulTime = &checkpointTime
+			backups[1].Status.Containers = []migrationv1.ContainerCheckpoint{
+				{Name: "web", Image: "registry.example.com/checkpoints/web-0:1"},
+			}
+			Expect(k8sClient.Status().Update(ctx, backups[1])).To(Succeed())
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &MigrationBackupReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+			statefulMigration := &migrationv1.StatefulMigration{
+				ObjectMeta: metav1.ObjectMeta{Name: "cleanup", Namespace: "default"},
+				Spec:       migrationv1.StatefulMigrationSpec{RemovedClusterPolicy: migrationv1.RemovedClusterRetain},
+				Status: migrationv1.StatefulMigrationStatus{
+					Migration: &migrationv1.MigrationStatus{Phase: migrationv1.MigrationRestoring},
+				},
+			}
+			currentPods := map[string][]corev1.Pod{
+				"member1": {{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default"}}},
+			}
+			exists := func(backup *migrationv1.CheckpointBackup) bool {
+				return !errors.IsNotFound(k8sClient.Get(ctx, types.NamespacedName{Name: backup.Name, Namespace: backup.Namespace}, &migrationv1.CheckpointBackup{}))
+			}
+
+			// A migration in progress still needs the backups of the cluster it moves away from
+			Expect(reconciler.cleanupOrphanedCheckpointBackups(ctx, statefulMigration, []string{"member1"}, currentPods)).To(Succeed())
+			Expect(exists(backups[1])).To(BeTrue())
+
+			statefulMigration.Status.Migration.Phase = migrationv1.MigrationCompleted
+			Expect(reconciler.cleanupOrphanedCheckpointBackups(ctx, statefulMigration, []string{"member1"}, currentPods)).To(Succeed())
+			Expect(exists(backups[0])).To(BeTrue())
+			Expect(exists(backups[1])).To(BeFalse())
+			Expect(recorder.Events).To(Receive(And(ContainSubstring(backups[1].Name), ContainSubstring("is no longer a source cluster"), ContainSubstring("retained"))))
+			Expect(statefulMigration.Status.RetainedCheckpoints).To(HaveLen(1))
+			retained := statefulMigration.Status.RetainedCheckpoints[0]
+			Expect(retained.Cluster).To(Equal("member3"))
+			Expect(retained.PodRef).To(Equal(backups[1].Spec.PodRef))
+			Expect(retained.Time.Equal(&checkpointTime)).To(BeTrue())
+			Expect(retained.Containers).To(Equal(backups[1].Status.Containers))
+
+			// The retained checkpoints are dropped once the cluster is a source cluster again
+			Expect(reconciler.cleanupOrphanedCheckpointBackups(ctx, statefulMigration, []string{"member1", "member3"}, currentPods)).To(Succeed())
+			Expect(statefulMigration.Status.RetainedCheckpoints).To(BeEmpty())
+		})
+	})
+
+	Context("When marking enrolled workloads", func() {
+		ctx := context.Background()
+
+		It("should only patch its own label or annotation", func() {
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "marker-web",
+					Namespace:   "default",
+					Labels:      map[string]string{"app": "marker-web"},
+					Annotations: map[string]string{"argocd.argoproj.io/tracking-id": "shop:apps/StatefulSet:default/marker-web"},
+				},
+				Spec: appsv1.StatefulSetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "marker-web"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "marker-web"}},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: "nginx:1.27"}}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, statefulSet)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, statefulSet)).To(Succeed())
+			})
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &MigrationBackupReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+			statefulMigration := &migrationv1.StatefulMigration{
+				ObjectMeta: metav1.ObjectMeta{Name: "marker", Namespace: "default"},
+			}
+			resourceRef := migrationv1.ResourceRef{APIVersion: "apps/v1", Kind: "StatefulSet", Namespace: "default", Name: "marker-web"}
+			current := func() *appsv1.StatefulSet {
+				workload := &appsv1.StatefulSet{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "marker-web", Namespace: "default"}, workload)).To(Succeed())
+				return workload
+			}
+
+			Expect(reconciler.addLabelToTargetResource(ctx, statefulMigration, resourceRef)).To(Succeed())
+			Expect(current().Labels).To(Equal(map[string]string{"app": "marker-web", CheckpointMigrationLabel: "true"}))
+			Expect(recorder.Events).To(Receive(And(ContainSubstring(ReasonWorkloadLabeled), ContainSubstring("Labeled StatefulSet"))))
+
+			// Already marked workloads are left alone
+			Expect(reconciler.addLabelToTargetResource(ctx, statefulMigration, resourceRef)).To(Succeed())
+			Expect(recorder.Events).NotTo(Receive())
+
+			// Switching to annotations moves the marker
+			reconciler.WorkloadMarker = WorkloadMarkerAnnotation
+			Expect(reconciler.addLabelToTargetResource(ctx, statefulMigration, resourceRef)).To(Succeed())
+			workload := current()
+			Expect(workload.Labels).To(Equal(map[string]string{"app": "marker-web"}))
+			Expect(workload.Annotations).To(HaveKeyWithValue(CheckpointMigrationLabel, "true"))
+			Expect(workload.Annotations).To(HaveKey("argocd.argoproj.io/tracking-id"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("Annotated StatefulSet")))
+
+			Expect(reconciler.removeLabelFromTargetResource(ctx, statefulMigration, resourceRef)).To(Succeed())
+			workload = current()
+			Expect(workload.Labels).To(Equal(map[string]string{"app": "marker-web"}))
+			Expect(workload.Annotations).To(Equal(map[string]string{"argocd.argoproj.io/tracking-id": "shop:apps/StatefulSet:default/marker-web"}))
+		})
+
+		It("should reject unknown markers", func() {
+			Expect(ParseWorkloadMarker("")).To(Equal(WorkloadMarkerLabel))
+			_, err := ParseWorkloadMarker("taint")
+			Expect(err).To(MatchError(ContainSubstring("unsupported workload marker")))
+		})
+	})
+
+	Context("When propagating the migration namespace", func() {
+		ctx := context.Background()
+
+		newStatefulMigration := func(name, migrationNamespace string, clusters ...string) *migrationv1.StatefulMigration {
+			return &migrationv1.StatefulMigration{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: migrationv1.StatefulMigrationSpec{
+					ResourceRef:        migrationv1.ResourceRef{APIVersion: "apps/v1", Kind: "StatefulSet", Name: name},
+					SourceClusters:     clusters,
+					MigrationNamespace: migrationNamespace,
+					Registry:           migrationv1.Registry{URL: "registry.example.com", Repository: "checkpoints"},
+					Schedule:           "*/5 * * * *",
+				},
+			}
+		}
+
+		It("should propagate each namespace to the union of the source clusters using it", func() {
+			statefulMigrations := []*migrationv1.StatefulMigration{
+				newStatefulMigration("union-a", "", "member1", "member2"),
+				newStatefulMigration("union-b", "", "member3", "member2"),
+				newStatefulMigration("union-c", "team-migration", "member4"),
+			}
+			for _, statefulMigration := range statefulMigrations {
+				Expect(k8sClient.Create(ctx, statefulMigration)).To(Succeed())
+			}
+			DeferCleanup(func() {
+				for _, statefulMigration := range statefulMigrations {
+					_ = k8sClient.Delete(ctx, statefulMigration)
+				}
+			})
+
+			reconciler := &MigrationBackupReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+			// The StatefulMigration being reconciled is used as is, even if the list is stale
+			reconciled := statefulMigrations[0].DeepCopy()
+			reconciled.Spec.SourceClusters = []string{"member5"}
+			clusters, err := reconciler.namespaceClusters(ctx, reconciled)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusters).To(Equal(map[string][]string{
+				DefaultMigrationNamespace: {"member2", "member3", "member5"},
+				"team-migration":          {"member4"},
+			}))
+
+			reconciled.DeletionTimestamp = ptr.To(metav1.Now())
+			clusters, err = reconciler.namespaceClusters(ctx, reconciled)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusters[DefaultMigrationNamespace]).To(Equal([]string{"member2", "member3"}))
+
+			// The operator's namespace applies to the StatefulMigrations that do not set one
+			reconciler.MigrationNamespace = "migration"
+			Expect(reconciler.migrationNamespace(statefulMigrations[1])).To(Equal("migration"))
+			Expect(reconciler.migrationNamespace(statefulMigrations[2])).To(Equal("team-migration"))
+		})
+	})
+
+	Context("When applying container rules", func() {
+		It("should give each container the strategy of the first matching rule", func() {
+			statefulMigration := &migrationv1.StatefulMigration{
+				Spec: migrationv1.StatefulMigrationSpec{
+					ContainerRules: []migrationv1.ContainerRule{
+						{Names: []string{"istio-*"}, Strategy: migrationv1.ContainerStrategySkip},
+						{Names: []string{"log-shipper", "istio-proxy"}, Strategy: migrationv1.ContainerStrategyRecreate},
+						{Names: []string{"["}, Strategy: migrationv1.ContainerStrategySkip},
+					},
+				},
+			}
+			pod := &corev1.Pod{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: "istio-init", Image: "istio/proxyv2:1.26"},
+						{Name: "migrate", Image: "app:1.0"},
+						{Name: "log-shipper", Image: "fluent-bit:4.0", RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways)},
+						{Name: "cache", Image: "redis:8", RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways)},
+					},
+					Containers: []corev1.Container{
+						{Name: "app", Image: "app:1.0"},
+						{Name: "istio-proxy", Image: "istio/proxyv2:1.26"},
+					},
+				},
+			}
+
+			reconciler := &MigrationBackupReconciler{}
+			Expect(reconciler.extractContainerInfo(statefulMigration, pod)).To(Equal([]migrationv1.Container{
+				{Name: "istio-init", Image: "istio/proxyv2:1.26", Strategy: migrationv1.ContainerStrategySkip},
+				{Name: "log-shipper", Image: "fluent-bit:4.0", Strategy: migrationv1.ContainerStrategyRecreate},
+				{Name: "cache", Image: "redis:8", Strategy: migrationv1.ContainerStrategyCheckpoint},
+				{Name: "app", Image: "app:1.0", Strategy: migrationv1.ContainerStrategyCheckpoint},
+				{Name: "istio-proxy", Image: "istio/proxyv2:1.26", Strategy: migrationv1.ContainerStrategySkip},
+			}))
+		})
+	})
+
+	Context("When mapping watched changes to StatefulMigrations", func() {
+		newStatefulMigration := func() *migrationv1.StatefulMigration {
+			return &migrationv1.StatefulMigration{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec: migrationv1.StatefulMigrationSpec{
+					ResourceRef:    migrationv1.ResourceRef{Kind: "StatefulSet", Name: "web"},
+					SourceClusters: []string{"member1"},
+				},
+			}
+		}
+
+		It("should index the enrolled workloads", func() {
+			statefulMigration := newStatefulMigration()
+			Expect(indexWorkloads(statefulMigration)).To(Equal([]string{"statefulset/default/web"}))
+
+			statefulMigration.Spec.WorkloadSelector = &migrationv1.WorkloadSelector{}
+			statefulMigration.Status.Workloads = []migrationv1.ResourceRef{
+				{Kind: "Deployment", Namespace: "shop", Name: "cart"},
+				{Kind: "Pod", Namespace: "default", Name: "worker"},
+			}
+			Expect(indexWorkloads(statefulMigration)).To(Equal([]string{"deployment/shop/cart", "pod/default/worker"}))
+		})
+
+		It("should match workloads against the WorkloadSelector", func() {
+			statefulMigration := newStatefulMigration()
+			workload := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+				Name: "cart", Namespace: "default", Labels: map[string]string{"backup": "true"},
+			}}
+			Expect(selectsWorkload(statefulMigration, workload, nil)).To(BeFalse())
+
+			statefulMigration.Spec.WorkloadSelector = &migrationv1.WorkloadSelector{
+				LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"backup": "true"}},
+			}
+			Expect(selectsWorkload(statefulMigration, workload, nil)).To(BeTrue())
+
+			workload.Namespace = "shop"
+			Expect(selectsWorkload(statefulMigration, workload, nil)).To(BeFalse())
+
+			statefulMigration.Spec.WorkloadSelector.NamespaceSelector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "shop"},
+			}
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"team": "shop"}}}
+			Expect(selectsWorkload(statefulMigration, workload, namespace)).To(BeTrue())
+			namespace.Labels = nil
+			Expect(selectsWorkload(statefulMigration, workload, namespace)).To(BeFalse())
+		})
+
+		It("should only map pods of enrolled workloads on source clusters", func() {
+			statefulMigration := newStatefulMigration()
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default"}}
+			Expect(watchesMemberPod(statefulMigration, MemberPod{Cluster: "member1", Pod: pod})).To(BeTrue())
+			Expect(watchesMemberPod(statefulMigration, MemberPod{Cluster: "member2", Pod: pod})).To(BeFalse())
+
+			pod.Namespace = "shop"
+			Expect(watchesMemberPod(statefulMigration, MemberPod{Cluster: "member1", Pod: pod})).To(BeFalse())
+
+			statefulMigration.Spec.ResourceRef = migrationv1.ResourceRef{Kind: "Pod", Namespace: "shop", Name: "worker"}
+			Expect(watchesMemberPod(statefulMigration, MemberPod{Cluster: "member1", Pod: pod})).To(BeFalse())
+			pod.Name = "worker"
+			Expect(watchesMemberPod(statefulMigration, MemberPod{Cluster: "member1", Pod: pod})).To(BeTrue())
+		})
+
+		It("should only watch the namespaces of the enrolled workloads", func() {
+			statefulMigration := newStatefulMigration()
+			Expect(memberPodScopes(statefulMigration, []string{"member1", "member2"})).To(Equal([]memberPodScope{
+				{Cluster: "member1", Namespace: "default"},
+				{Cluster: "member2", Namespace: "default"},
+			}))
+			Expect(indexMemberPodScopes(statefulMigration)).To(Equal([]string{"member1/default"}))
+
+			statefulMigration.Spec.ResourceRef = migrationv1.ResourceRef{Kind: "Pod", Namespace: "shop", Name: "worker"}
+			Expect(indexMemberPodScopes(statefulMigration)).To(Equal([]string{"member1/shop"}))
+		})
+
+		It("should only notify pod changes that affect the backups", func() {
+			oldPod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", ResourceVersion: "1"},
+				Spec:       corev1.PodSpec{NodeName: "node-1"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			}
+			newPod := oldPod.DeepCopy()
+			newPod.ResourceVersion = "2"
+			newPod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			Expect(memberPodChanged(oldPod, newPod)).To(BeFalse())
+
+			newPod.Spec.NodeName = "node-2"
+			Expect(memberPodChanged(oldPod, newPod)).To(BeTrue())
+
+			newPod = oldPod.DeepCopy()
+			newPod.Labels = map[string]string{CheckpointMigrationLabel: "true"}
+			Expect(memberPodChanged(oldPod, newPod)).To(BeTrue())
+
+			newPod = oldPod.DeepCopy()
+			newPod.DeletionTimestamp = ptr.To(metav1.Now())
+			Expect(memberPodChanged(oldPod, newPod)).To(BeTrue())
+		})
+	})
 })
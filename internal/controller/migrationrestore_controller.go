@@ -18,30 +18,92 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
 	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
 )
 
+const (
+	// MigrationRestoreFinalizer ensures the CheckpointRestores of a migration are cleaned up
+	MigrationRestoreFinalizer = "migrationrestore.migration.dcnlab.com/finalizer"
+
+	// migrationPollInterval is how often the progress of a migration on the member clusters is checked
+	migrationPollInterval = 10 * time.Second
+
+	// DefaultMigrationHealthTimeout is how long restored pods have to become ready if Spec.Rollback does not say
+	DefaultMigrationHealthTimeout = 10 * time.Minute
+
+	// FinalCheckpointTimeout is how long the source pods have to be checkpointed once the final checkpoint is requested.
+	// The request is served by a single run of the agent, so a pod that is not running then is never checkpointed for it.
+	FinalCheckpointTimeout = 10 * time.Minute
+)
+
+// Reasons used in StatefulMigration events
+const (
+	ReasonMigrationStarted         = "MigrationStarted"
+	ReasonFinalCheckpointRequested = "FinalCheckpointRequested"
+	ReasonRestoreCreated           = "RestoreCreated"
+	ReasonRestoringPods            = "RestoringPods"
+	ReasonSwitchingPlacement       = "SwitchingPlacement"
+	ReasonVerifyingPods            = "VerifyingPods"
+	ReasonMigrationCompleted       = "MigrationCompleted"
+	ReasonMigrationFailed          = "MigrationFailed"
+	ReasonRollbackStarted          = "RollbackStarted"
+	ReasonRolledBack               = "RolledBack"
+)
+
 // MigrationRestoreReconciler reconciles a StatefulMigration object for restore operations
 type MigrationRestoreReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme              *runtime.Scheme
+	KarmadaClient       *KarmadaClient
+	MemberClusterClient *MemberClusterClient
+	Recorder            record.EventRecorder
+	// Karmada provides the Karmada and member cluster clients, overriding KarmadaClient and MemberClusterClient
+	Karmada *KarmadaConnection
+
+	// now returns the current time, overridable for tests
+	now func() time.Time
 }
 
-// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=statefulmigrations,verbs=get;list;watch
-// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=statefulmigrations/status,verbs=get
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=statefulmigrations,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=statefulmigrations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=statefulmigrations/finalizers,verbs=update
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=checkpointbackups,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=migration.dcnlab.com,resources=checkpointrestores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-// This controller watches StatefulMigration resources but does nothing at the moment.
+// It migrates the workload of a StatefulMigration to Spec.TargetCluster: it takes a final
+// checkpoint of the source pods, restores them on the target cluster and, once the restored
+// pods are ready, moves the workload's PropagationPolicy placement to the target cluster.
 func (r *MigrationRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
+	r.ensureKarmadaClients()
+
 	// Fetch the StatefulMigration instance
 	var statefulMigration migrationv1.StatefulMigration
 	if err := r.Get(ctx, req.NamespacedName, &statefulMigration); err != nil {
@@ -52,15 +114,1118 @@ func (r *MigrationRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	log.Info("MigrationRestore controller received StatefulMigration", "name", statefulMigration.Name, "namespace", statefulMigration.Namespace)
+	// Handle deletion
+	if statefulMigration.GetDeletionTimestamp() != nil {
+		return r.reconcileDelete(ctx, &statefulMigration)
+	}
+
+	if statefulMigration.Spec.TargetCluster == "" {
+		return ctrl.Result{}, nil
+	}
+
+	// Add finalizer so that the CheckpointRestores created on the target are cleaned up
+	if !controllerutil.ContainsFinalizer(&statefulMigration, MigrationRestoreFinalizer) {
+		controllerutil.AddFinalizer(&statefulMigration, MigrationRestoreFinalizer)
+		if err := r.Update(ctx, &statefulMigration); err != nil {
+			log.Error(err, "Failed to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Status changes are collected on the fetched object and written once through the status subresource
+	originalStatus := statefulMigration.Status.DeepCopy()
+
+	result, err := r.reconcileMigration(ctx, &statefulMigration)
+	r.recordPhaseEvent(&statefulMigration, originalStatus.Migration)
+
+	if !equality.Semantic.DeepEqual(&statefulMigration.Status, originalStatus) {
+		if updateErr := r.Status().Update(ctx, &statefulMigration); updateErr != nil {
+			log.Error(updateErr, "Failed to update StatefulMigration status")
+			if err == nil {
+				return ctrl.Result{}, updateErr
+			}
+		}
+	}
+
+	return result, err
+}
+
+// reconcileMigration runs the current phase of the migration and moves it to the next one when done
+func (r *MigrationRestoreReconciler) reconcileMigration(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	targetCluster := statefulMigration.Spec.TargetCluster
+
+	// A new target cluster starts a new migration
+	migration := statefulMigration.Status.Migration
+	if migration == nil || migration.TargetCluster != targetCluster {
+		now := metav1.NewTime(r.clock())
+		migration = &migrationv1.MigrationStatus{
+			TargetCluster: targetCluster,
+			Phase:         migrationv1.MigrationPending,
+			StartTime:     &now,
+		}
+		statefulMigration.Status.Migration = migration
+		log.Info("Starting migration", "targetCluster", targetCluster)
+	}
+
+	if isMigrationFinished(migration.Phase) {
+		return ctrl.Result{}, nil
+	}
+
+	// The source clusters follow the workload once it is moved, so they are only checked before the migration starts
+	if migration.Phase == migrationv1.MigrationPending && slices.Contains(sourceClusters(statefulMigration), targetCluster) {
+		r.failMigration(migration, fmt.Sprintf("Target cluster %s is one of the source clusters", targetCluster))
+		return ctrl.Result{}, nil
+	}
+
+	// The restored pods are only adopted by StatefulSets and Deployments, any other workload would run them as duplicates
+	if migration.Phase == migrationv1.MigrationPending {
+		for _, workload := range enrolledWorkloads(statefulMigration) {
+			if !isMigratableResource(workload) {
+				r.failMigration(migration, fmt.Sprintf("%s %s cannot be migrated: only StatefulSets, Deployments and Pods can take over their restored pods",
+					workload.Kind, workload.Name))
+				return ctrl.Result{}, nil
+			}
+		}
+	}
+
+	if r.KarmadaClient == nil || r.MemberClusterClient == nil {
+		migration.Message = "Waiting for the Karmada client to be available"
+		return ctrl.Result{RequeueAfter: migrationPollInterval}, nil
+	}
+
+	// Restored pods that are not healthy in time are rolled back
+	switch migration.Phase {
+	case migrationv1.MigrationRestoring, migrationv1.MigrationSwitchingPlacement, migrationv1.MigrationVerifying:
+		if timeout := healthTimeout(statefulMigration); migration.RestoreStartTime != nil && r.clock().Sub(migration.RestoreStartTime.Time) > timeout {
+			r.startRollback(statefulMigration, fmt.Sprintf("Restored pods did not become ready on cluster %s within %s", migration.TargetCluster, timeout))
+		}
+	}
+
+	var err error
+	switch migration.Phase {
+	case migrationv1.MigrationPending:
+		if r.checkPreflight(statefulMigration) {
+			err = r.requestFinalCheckpoint(ctx, statefulMigration)
+		}
+	case migrationv1.MigrationCheckpointing:
+		err = r.waitForFinalCheckpoint(ctx, statefulMigration)
+	case migrationv1.MigrationRestoring:
+		err = r.waitForRestoredPods(ctx, statefulMigration)
+	case migrationv1.MigrationSwitchingPlacement:
+		err = r.switchPlacement(ctx, statefulMigration)
+	case migrationv1.MigrationVerifying:
+		err = r.verifyRestoredPods(ctx, statefulMigration)
+	case migrationv1.MigrationRollingBack:
+		err = r.rollBack(ctx, statefulMigration)
+	}
+	if err != nil {
+		migration.Message = err.Error()
+		return ctrl.Result{}, err
+	}
+
+	if isMigrationFinished(migration.Phase) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: migrationPollInterval}, nil
+}
+
+// checkPreflight reports whether the pre-flight checks found the target cluster able to restore the checkpoints
+// of the source clusters. It fails the migration if they did not, and waits for checks that have not run yet.
+func (r *MigrationRestoreReconciler) checkPreflight(statefulMigration *migrationv1.StatefulMigration) bool {
+	migration := statefulMigration.Status.Migration
+
+	// The checks are run by the backup controller, which also inspects the target cluster once it is set
+	condition := meta.FindStatusCondition(statefulMigration.Status.Conditions, migrationv1.StatefulMigrationConditionCompatible)
+	checked := slices.ContainsFunc(statefulMigration.Status.Preflight, func(preflight migrationv1.ClusterPreflight) bool {
+		return preflight.Name == migration.TargetCluster
+	})
+	if condition == nil || condition.ObservedGeneration != statefulMigration.Generation || !checked {
+		migration.Message = fmt.Sprintf("Waiting for the pre-flight checks of cluster %s", migration.TargetCluster)
+		return false
+	}
+
+	if condition.Status != metav1.ConditionTrue {
+		r.failMigration(migration, fmt.Sprintf("Pre-flight checks failed: %s", condition.Message))
+		return false
+	}
+	return true
+}
+
+// requestFinalCheckpoint asks the agents on the source clusters for an immediate checkpoint of every pod
+func (r *MigrationRestoreReconciler) requestFinalCheckpoint(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) error {
+	log := logf.FromContext(ctx)
+	migration := statefulMigration.Status.Migration
+
+	backups, err := r.listCheckpointBackups(ctx, statefulMigration)
+	if err != nil {
+		return fmt.Errorf("failed to list CheckpointBackups: %w", err)
+	}
+	if len(backups) == 0 {
+		migration.Message = "Waiting for CheckpointBackups of the source pods"
+		return nil
+	}
+
+	requestTime := metav1.NewTime(r.clock().Truncate(time.Second))
+	for i := range backups {
+		backup := &backups[i]
+		patch := client.MergeFrom(backup.DeepCopy())
+		if backup.Annotations == nil {
+			backup.Annotations = make(map[string]string)
+		}
+		backup.Annotations[CheckpointRequestAnnotation] = requestTime.UTC().Format(time.RFC3339)
+		if err := r.Patch(ctx, backup, patch); err != nil {
+			return fmt.Errorf("failed to request checkpoint from CheckpointBackup %s: %w", backup.Name, err)
+		}
+	}
+
+	log.Info("Requested final checkpoint", "backups", len(backups), "requestTime", requestTime)
+	migration.CheckpointRequestTime = &requestTime
+	migration.Phase = migrationv1.MigrationCheckpointing
+	migration.Message = fmt.Sprintf("Waiting for the final checkpoint of %d pod(s)", len(backups))
+	return nil
+}
+
+// waitForFinalCheckpoint waits for the requested checkpoints to be pushed and then restores the pods on the target cluster
+func (r *MigrationRestoreReconciler) waitForFinalCheckpoint(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) error {
+	migration := statefulMigration.Status.Migration
+
+	backups, err := r.listCheckpointBackups(ctx, statefulMigration)
+	if err != nil {
+		return fmt.Errorf("failed to list CheckpointBackups: %w", err)
+	}
+
+	// The checkpoint status is reported by the agent on the source cluster
+	expired := migration.CheckpointRequestTime != nil && r.clock().Sub(migration.CheckpointRequestTime.Time) > FinalCheckpointTimeout
+	checkpointed := make([]*migrationv1.CheckpointBackup, 0, len(backups))
+	for i := range backups {
+		cluster := backups[i].Labels["target-cluster"]
+		memberBackup, err := r.MemberClusterClient.GetCheckpointBackupFromCluster(ctx, cluster, backups[i].Namespace, backups[i].Name)
+		if err != nil {
+			return err
+		}
+
+		status := memberBackup.Status
+		podName := memberBackup.Spec.PodRef.Name
+		// The agent moves the backup to Checkpointing in the same status write that schedules the requested run, so
+		// the phase of a backup scheduled at or after the request is the result of that run. The completion times are
+		// taken from the member cluster clock and cannot be compared with the request time.
+		scheduled := status.LastScheduleTime != nil && !status.LastScheduleTime.Before(migration.CheckpointRequestTime)
+		reason := ""
+		if condition := meta.FindStatusCondition(status.Conditions, migrationv1.CheckpointBackupConditionReady); scheduled && condition != nil {
+			reason = ": " + condition.Message
+		}
+
+		switch {
+		case scheduled && status.Phase == migrationv1.CheckpointBackupSucceeded:
+			checkpointed = append(checkpointed, memberBackup)
+			continue
+		case scheduled && status.Phase == migrationv1.CheckpointBackupFailed:
+			r.failMigration(migration, fmt.Sprintf("Final checkpoint of pod %s on cluster %s failed%s", podName, cluster, reason))
+			return nil
+		case expired:
+			// A pod that was not running when the request was served is not checkpointed until the next schedule,
+			// which may never come for a suspended backup
+			r.failMigration(migration, fmt.Sprintf("Final checkpoint of pod %s on cluster %s did not complete within %s%s", podName, cluster, FinalCheckpointTimeout, reason))
+			return nil
+		default:
+			migration.Message = fmt.Sprintf("Waiting for the final checkpoint of pod %s on cluster %s", podName, cluster)
+			return nil
+		}
+	}
+
+	// Restore the pods on the target cluster from the final checkpoint
+	if err := r.MemberClusterClient.EnsureCRD(ctx, migration.TargetCluster); err != nil {
+		return err
+	}
+
+	migration.Restores = nil
+	if err := r.createRestores(ctx, statefulMigration, checkpointed); err != nil {
+		return err
+	}
+
+	now := metav1.NewTime(r.clock())
+	migration.RestoreStartTime = &now
+	migration.Phase = migrationv1.MigrationRestoring
+	migration.Message = fmt.Sprintf("Restoring %d pod(s) on cluster %s", len(migration.Restores), migration.TargetCluster)
+	return nil
+}
+
+// createRestores creates the CheckpointRestores of the checkpointed pods that are not restored yet.
+// The pods of a StatefulSet are restored onto their ordinals and, unless the StatefulSet uses the
+// Parallel pod management policy, one at a time in increasing ordinal order like the StatefulSet
+// controller would create them.
+func (r *MigrationRestoreReconciler) createRestores(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, backups []*migrationv1.CheckpointBackup) error {
+	log := logf.FromContext(ctx)
+	migration := statefulMigration.Status.Migration
+
+	restored := make(map[string]bool)
+	for _, ref := range migration.Restores {
+		restored[ref.BackupName] = true
+	}
+	var pending []*migrationv1.CheckpointBackup
+	for _, backup := range backups {
+		if !restored[backup.Name] {
+			pending = append(pending, backup)
+		}
+	}
+
+	// The pods of each StatefulSet are ordered on their own
+	var next []*migrationv1.CheckpointBackup
+	var workloads []migrationv1.ResourceRef
+	statefulSetBackups := make(map[migrationv1.ResourceRef][]*migrationv1.CheckpointBackup)
+	for _, backup := range pending {
+		workload := backup.Spec.ResourceRef
+		if !isStatefulSetResource(workload) {
+			next = append(next, backup)
+			continue
+		}
+		if _, found := statefulSetBackups[workload]; !found {
+			workloads = append(workloads, workload)
+		}
+		statefulSetBackups[workload] = append(statefulSetBackups[workload], backup)
+	}
+
+	statefulSets := make(map[migrationv1.ResourceRef]*appsv1.StatefulSet)
+	for _, workload := range workloads {
+		statefulSet, err := r.getStatefulSet(ctx, workload)
+		if err != nil {
+			return err
+		}
+		statefulSets[workload] = statefulSet
+		next = append(next, nextStatefulSetRestores(statefulSet, statefulSetBackups[workload])...)
+	}
+
+	for _, backup := range next {
+		ref, err := r.createCheckpointRestore(ctx, statefulMigration, backup, statefulSets[backup.Spec.ResourceRef])
+		if err != nil {
+			return err
+		}
+		log.Info("Created CheckpointRestore", "restore", ref.Name, "pod", ref.PodName, "targetCluster", migration.TargetCluster)
+		r.Recorder.Eventf(statefulMigration, corev1.EventTypeNormal, ReasonRestoreCreated,
+			"Created CheckpointRestore %s to restore pod %s on cluster %s", ref.Name, ref.PodName, migration.TargetCluster)
+		migration.Restores = append(migration.Restores, *ref)
+	}
+	return nil
+}
+
+// isStatefulSetResource reports whether the resource reference points to a StatefulSet
+func isStatefulSetResource(resourceRef migrationv1.ResourceRef) bool {
+	return strings.EqualFold(resourceRef.Kind, "StatefulSet")
+}
+
+// isDeploymentResource reports whether the resource reference points to a Deployment
+func isDeploymentResource(resourceRef migrationv1.ResourceRef) bool {
+	return strings.EqualFold(resourceRef.Kind, "Deployment")
+}
+
+// isMigratableResource reports whether the restored pods of the workload are taken over on the target cluster:
+// by the StatefulSet or the ReplicaSet of the Deployment that adopts them, or as the workload itself for a Pod
+func isMigratableResource(resourceRef migrationv1.ResourceRef) bool {
+	return isStatefulSetResource(resourceRef) || isDeploymentResource(resourceRef) || isPodResource(resourceRef)
+}
+
+// getStatefulSet returns the StatefulSet of the resource reference, or nil if it references another kind
+func (r *MigrationRestoreReconciler) getStatefulSet(ctx context.Context, resourceRef migrationv1.ResourceRef) (*appsv1.StatefulSet, error) {
+	if !isStatefulSetResource(resourceRef) {
+		return nil, nil
+	}
+	statefulSet := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{Name: resourceRef.Name, Namespace: resourceRef.Namespace}, statefulSet); err != nil {
+		return nil, fmt.Errorf("failed to get StatefulSet %s: %w", resourceRef.Name, err)
+	}
+	return statefulSet, nil
+}
+
+// nextStatefulSetRestores returns the backups of the StatefulSet's pods to restore next, in ordinal order.
+// With the OrderedReady pod management policy only the lowest pending ordinal is returned, so that it is
+// ready before the next one starts. Backups of pods that are not ordinals of the StatefulSet are dropped.
+func nextStatefulSetRestores(statefulSet *appsv1.StatefulSet, backups []*migrationv1.CheckpointBackup) []*migrationv1.CheckpointBackup {
+	ordinals := make(map[string]int32)
+	var next []*migrationv1.CheckpointBackup
+	for _, backup := range backups {
+		ordinal, ok := statefulSetOrdinal(statefulSet.Name, backup.Spec.PodRef.Name)
+		if !ok {
+			continue
+		}
+		ordinals[backup.Name] = ordinal
+		next = append(next, backup)
+	}
+
+	slices.SortFunc(next, func(a, b *migrationv1.CheckpointBackup) int {
+		return int(ordinals[a.Name] - ordinals[b.Name])
+	})
+
+	if statefulSet.Spec.PodManagementPolicy != appsv1.ParallelPodManagement && len(next) > 1 {
+		next = next[:1]
+	}
+	return next
+}
+
+// createCheckpointRestore creates a CheckpointRestore for the final checkpoint of a pod and propagates it to the target cluster.
+// The pods of a StatefulSet keep the identity of their ordinal.
+func (r *MigrationRestoreReconciler) createCheckpointRestore(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, backup *migrationv1.CheckpointBackup, statefulSet *appsv1.StatefulSet) (*migrationv1.MigrationRestoreRef, error) {
+	targetCluster := statefulMigration.Spec.TargetCluster
+
+	namespace := backup.Spec.PodRef.Namespace
+	if namespace == "" {
+		namespace = backup.Namespace
+	}
+
+	// Containers that are recreated or skipped carry their strategy over from the backup
+	var containers []migrationv1.Container
+	for _, checkpoint := range backup.Status.Containers {
+		containers = append(containers, migrationv1.Container{Name: checkpoint.Name, Image: checkpoint.Image})
+	}
+	for _, container := range backup.Spec.Containers {
+		if container.Strategy == migrationv1.ContainerStrategyRecreate || container.Strategy == migrationv1.ContainerStrategySkip {
+			containers = append(containers, container)
+		}
+	}
+
+	template, err := r.getPodTemplate(ctx, backup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod template of %s: %w", backup.Spec.PodRef.Name, err)
+	}
+
+	var identity *migrationv1.StatefulSetIdentity
+	if statefulSet != nil {
+		identity, err = r.getStatefulSetIdentity(ctx, statefulSet, backup)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var deployment *migrationv1.DeploymentIdentity
+	if isDeploymentResource(backup.Spec.ResourceRef) {
+		if deployment, err = r.getDeploymentIdentity(ctx, backup); err != nil {
+			return nil, err
+		}
+	}
+
+	// The restore lives in the pod's namespace, so the restored pod replaces the original one
+	restore := &migrationv1.CheckpointRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-restore", backup.Name),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"stateful-migration":           statefulMigration.Name,
+				"stateful-migration-namespace": statefulMigration.Namespace,
+				"target-cluster":               targetCluster,
+				"target-pod":                   backup.Spec.PodRef.Name,
+			},
+		},
+		Spec: migrationv1.CheckpointRestoreSpec{
+			BackupRef:   migrationv1.BackupRef{Name: backup.Name},
+			PodName:     backup.Spec.PodRef.Name,
+			Containers:  containers,
+			PodTemplate: template,
+			StatefulSet: identity,
+			Deployment:  deployment,
+		},
+	}
+
+	if err := r.applyCheckpointRestore(ctx, restore, targetCluster); err != nil {
+		return nil, err
+	}
+
+	ref := &migrationv1.MigrationRestoreRef{
+		Name:       restore.Name,
+		Namespace:  restore.Namespace,
+		BackupName: backup.Name,
+		Cluster:    targetCluster,
+		PodName:    restore.Spec.PodName,
+	}
+	if identity != nil {
+		ref.Ordinal = &identity.Ordinal
+	}
+	return ref, nil
+}
+
+// applyCheckpointRestore creates or updates the CheckpointRestore and propagates it to the cluster
+func (r *MigrationRestoreReconciler) applyCheckpointRestore(ctx context.Context, restore *migrationv1.CheckpointRestore, cluster string) error {
+	var existing migrationv1.CheckpointRestore
+	if err := r.Get(ctx, client.ObjectKeyFromObject(restore), &existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		if err := r.Create(ctx, restore); err != nil {
+			return fmt.Errorf("failed to create CheckpointRestore %s: %w", restore.Name, err)
+		}
+	} else {
+		existing.Labels = restore.Labels
+		existing.Spec = restore.Spec
+		if err := r.Update(ctx, &existing); err != nil {
+			return fmt.Errorf("failed to update CheckpointRestore %s: %w", restore.Name, err)
+		}
+		restore = &existing
+	}
+
+	// The policy is owned by the CheckpointRestore and deleted with it
+	policy, err := checkpointPropagationPolicy(restore, r.Scheme, resourceTypeCheckpointRestore, cluster)
+	if err != nil {
+		return err
+	}
+	if _, err := r.KarmadaClient.CreateOrUpdatePropagationPolicy(ctx, policy); err != nil {
+		return fmt.Errorf("failed to propagate CheckpointRestore %s: %w", restore.Name, err)
+	}
+
+	return nil
+}
+
+// getStatefulSetIdentity returns the StatefulSet ordinal the pod of a CheckpointBackup is restored onto
+func (r *MigrationRestoreReconciler) getStatefulSetIdentity(ctx context.Context, statefulSet *appsv1.StatefulSet, backup *migrationv1.CheckpointBackup) (*migrationv1.StatefulSetIdentity, error) {
+	ordinal, ok := statefulSetOrdinal(statefulSet.Name, backup.Spec.PodRef.Name)
+	if !ok {
+		return nil, fmt.Errorf("pod %s is not an ordinal of StatefulSet %s", backup.Spec.PodRef.Name, statefulSet.Name)
+	}
 
-	// TODO: Implement restore logic here when needed
-	// This controller will handle restore operations for StatefulMigration resources
-	// For now, it does nothing but log that it received the resource
+	// Keep the revision of the source pod, so the StatefulSet does not roll the restored pod on adoption
+	revisionHash := statefulSet.Status.CurrentRevision
+	pod, err := r.MemberClusterClient.GetPodFromCluster(ctx, backup.Labels["target-cluster"], backup.Spec.PodRef.Namespace, backup.Spec.PodRef.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil && pod.Labels[appsv1.StatefulSetRevisionLabel] != "" {
+		revisionHash = pod.Labels[appsv1.StatefulSetRevisionLabel]
+	}
+
+	return &migrationv1.StatefulSetIdentity{
+		Name:                 statefulSet.Name,
+		Ordinal:              ordinal,
+		ServiceName:          statefulSet.Spec.ServiceName,
+		RevisionHash:         revisionHash,
+		VolumeClaimTemplates: statefulSet.Spec.VolumeClaimTemplates,
+	}, nil
+}
+
+// getDeploymentIdentity returns the ReplicaSet the pod of a CheckpointBackup is handed over to, from the
+// pod-template-hash of the source pod. Once the source pod is gone, as during a rollback, the hash is taken from
+// the CheckpointRestore that restored the pod on the target cluster.
+func (r *MigrationRestoreReconciler) getDeploymentIdentity(ctx context.Context, backup *migrationv1.CheckpointBackup) (*migrationv1.DeploymentIdentity, error) {
+	namespace := backup.Spec.PodRef.Namespace
+	if namespace == "" {
+		namespace = backup.Namespace
+	}
+
+	pod, err := r.MemberClusterClient.GetPodFromCluster(ctx, backup.Labels["target-cluster"], namespace, backup.Spec.PodRef.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil && pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey] != "" {
+		return &migrationv1.DeploymentIdentity{
+			Name:            backup.Spec.ResourceRef.Name,
+			PodTemplateHash: pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey],
+		}, nil
+	}
+
+	var restore migrationv1.CheckpointRestore
+	err = r.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("%s-restore", backup.Name), Namespace: namespace}, &restore)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil && restore.Spec.Deployment != nil {
+		return restore.Spec.Deployment.DeepCopy(), nil
+	}
+	return nil, fmt.Errorf("pod %s of Deployment %s has no %s label to hand the restored pod over to its ReplicaSet",
+		backup.Spec.PodRef.Name, backup.Spec.ResourceRef.Name, appsv1.DefaultDeploymentUniqueLabelKey)
+}
+
+// getPodTemplate returns the pod template the pod of a CheckpointBackup is restored into, from the backup's workload
+func (r *MigrationRestoreReconciler) getPodTemplate(ctx context.Context, backup *migrationv1.CheckpointBackup) (*corev1.PodTemplateSpec, error) {
+	resourceRef := backup.Spec.ResourceRef
+
+	if isPodResource(resourceRef) {
+		// Pods only exist on the member clusters
+		pod, err := r.MemberClusterClient.GetPodFromCluster(ctx, backup.Labels["target-cluster"], backup.Spec.PodRef.Namespace, backup.Spec.PodRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		return &corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      pod.Labels,
+				Annotations: pod.Annotations,
+			},
+			Spec: *sanitizePodSpec(pod.Spec.DeepCopy()),
+		}, nil
+	}
+
+	workload, err := getWorkload(ctx, r.Client, resourceRef)
+	if err != nil {
+		return nil, err
+	}
+	return workloadPodTemplate(workload)
+}
+
+// waitForRestoredPods waits for every restored pod on the target cluster to be ready
+func (r *MigrationRestoreReconciler) waitForRestoredPods(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) error {
+	migration := statefulMigration.Status.Migration
+
+	ready := 0
+	for i := range migration.Restores {
+		ref := &migration.Restores[i]
+
+		restore, err := r.MemberClusterClient.GetCheckpointRestoreFromCluster(ctx, migration.TargetCluster, ref.Namespace, ref.Name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// Not propagated yet
+				continue
+			}
+			return err
+		}
+
+		switch restore.Status.Phase {
+		case migrationv1.CheckpointRestoreFailed:
+			message := fmt.Sprintf("Restore of pod %s on cluster %s failed", ref.PodName, migration.TargetCluster)
+			if condition := meta.FindStatusCondition(restore.Status.Conditions, migrationv1.CheckpointRestoreConditionReady); condition != nil {
+				message = fmt.Sprintf("%s: %s", message, condition.Message)
+			}
+			r.startRollback(statefulMigration, message)
+			return nil
+		case migrationv1.CheckpointRestoreRestored:
+			pod, err := r.MemberClusterClient.GetPodFromCluster(ctx, migration.TargetCluster, ref.Namespace, ref.PodName)
+			if err != nil {
+				return err
+			}
+			ref.Ready = isPodReady(pod)
+		}
+
+		if ref.Ready {
+			ready++
+		}
+	}
+
+	if ready < len(migration.Restores) {
+		migration.Message = fmt.Sprintf("%d/%d restored pod(s) ready on cluster %s", ready, len(migration.Restores), migration.TargetCluster)
+		return nil
+	}
+
+	// StatefulSet ordinals restored one at a time are continued once the previous ones are ready
+	created, err := r.restoreNextOrdinals(ctx, statefulMigration)
+	if err != nil {
+		return err
+	}
+	if created {
+		migration.Message = fmt.Sprintf("Restoring %d pod(s) on cluster %s", len(migration.Restores), migration.TargetCluster)
+		return nil
+	}
+
+	// Only move the workload once every restored pod is ready, since that removes the source replicas
+	migration.Phase = migrationv1.MigrationSwitchingPlacement
+	migration.Message = fmt.Sprintf("Moving %s to cluster %s", describeWorkloads(statefulMigration), migration.TargetCluster)
+	return nil
+}
+
+// restoreNextOrdinals creates the CheckpointRestores of the checkpointed pods that were held back
+// by the StatefulSet pod management policy. It reports whether any was created.
+func (r *MigrationRestoreReconciler) restoreNextOrdinals(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) (bool, error) {
+	migration := statefulMigration.Status.Migration
+
+	backups, err := r.listCheckpointBackups(ctx, statefulMigration)
+	if err != nil {
+		return false, fmt.Errorf("failed to list CheckpointBackups: %w", err)
+	}
+
+	restored := make(map[string]bool)
+	for _, ref := range migration.Restores {
+		restored[ref.BackupName] = true
+	}
+	var pending []*migrationv1.CheckpointBackup
+	for i := range backups {
+		if restored[backups[i].Name] {
+			continue
+		}
+		cluster := backups[i].Labels["target-cluster"]
+		memberBackup, err := r.MemberClusterClient.GetCheckpointBackupFromCluster(ctx, cluster, backups[i].Namespace, backups[i].Name)
+		if err != nil {
+			return false, err
+		}
+		pending = append(pending, memberBackup)
+	}
+	if len(pending) == 0 {
+		return false, nil
+	}
+
+	count := len(migration.Restores)
+	if err := r.createRestores(ctx, statefulMigration, pending); err != nil {
+		return false, err
+	}
+	return len(migration.Restores) > count, nil
+}
+
+// switchPlacement rewrites the placement of the workloads' PropagationPolicies from the source clusters to the target cluster
+func (r *MigrationRestoreReconciler) switchPlacement(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) error {
+	log := logf.FromContext(ctx)
+	migration := statefulMigration.Status.Migration
+
+	policies, err := r.findWorkloadPropagationPolicies(ctx, statefulMigration)
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		if policy.Spec.Placement.ClusterAffinities != nil {
+			r.failMigration(migration, fmt.Sprintf("PropagationPolicy %s/%s uses clusterAffinities, which cannot be rewritten", policy.Namespace, policy.Name))
+			return nil
+		}
+	}
+
+	for _, policy := range policies {
+		// Keep the clusters that are not migrated away from and add the target
+		var placedClusters, clusterNames []string
+		if policy.Spec.Placement.ClusterAffinity != nil {
+			placedClusters = policy.Spec.Placement.ClusterAffinity.ClusterNames
+			for _, cluster := range placedClusters {
+				if !slices.Contains(sourceClusters(statefulMigration), cluster) {
+					clusterNames = append(clusterNames, cluster)
+				}
+			}
+		}
+		if !slices.Contains(clusterNames, migration.TargetCluster) {
+			clusterNames = append(clusterNames, migration.TargetCluster)
+		}
+
+		// The placement before the migration is only recorded once, a retry finds the policy already moved
+		if !slices.ContainsFunc(migration.SourcePlacements, func(placement migrationv1.PolicyPlacement) bool {
+			return placement.Namespace == policy.Namespace && placement.Name == policy.Name
+		}) {
+			migration.SourcePlacements = append(migration.SourcePlacements, migrationv1.PolicyPlacement{
+				Namespace:    policy.Namespace,
+				Name:         policy.Name,
+				ClusterNames: slices.Clone(placedClusters),
+			})
+		}
+
+		policy.Spec.Placement.ClusterAffinity = &karmadav1alpha1.ClusterAffinity{
+			ClusterNames: clusterNames,
+		}
+		if err := r.KarmadaClient.Update(ctx, policy); err != nil {
+			return fmt.Errorf("failed to update PropagationPolicy %s/%s: %w", policy.Namespace, policy.Name, err)
+		}
+		log.Info("Moved workload placement", "policy", policy.Name, "clusters", clusterNames)
+	}
+
+	migration.Phase = migrationv1.MigrationVerifying
+	migration.Message = fmt.Sprintf("Verifying the restored pods of %s on cluster %s", describeWorkloads(statefulMigration), migration.TargetCluster)
+	return nil
+}
+
+// verifyRestoredPods completes the migration once the restored pods are ready after the workload was moved.
+// Restored StatefulSet and Deployment pods must also have been adopted by the StatefulSet or ReplicaSet on the
+// target cluster, so that the workload runs the restored state rather than new replicas next to it.
+func (r *MigrationRestoreReconciler) verifyRestoredPods(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) error {
+	migration := statefulMigration.Status.Migration
+
+	for i := range migration.Restores {
+		ref := &migration.Restores[i]
+
+		pod, err := r.MemberClusterClient.GetPodFromCluster(ctx, migration.TargetCluster, ref.Namespace, ref.PodName)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		ref.Ready = err == nil && isPodReady(pod)
+		if !ref.Ready {
+			migration.Message = fmt.Sprintf("Waiting for restored pod %s to be ready on cluster %s", ref.PodName, migration.TargetCluster)
+			return nil
+		}
+		// Restored StatefulSet and Deployment pods have no controller until their workload adopts them
+		if metav1.GetControllerOf(pod) == nil {
+			owner := "ReplicaSet"
+			if ref.Ordinal != nil {
+				owner = "StatefulSet"
+			}
+			migration.Message = fmt.Sprintf("Waiting for restored pod %s to be adopted by its %s", ref.PodName, owner)
+			return nil
+		}
+	}
+
+	now := metav1.NewTime(r.clock())
+	migration.Phase = migrationv1.MigrationCompleted
+	migration.CompletionTime = &now
+	migration.Message = fmt.Sprintf("%s migrated to cluster %s", describeWorkloads(statefulMigration), migration.TargetCluster)
+	return nil
+}
+
+// recordPhaseEvent records an event when the migration starts or moves to another phase.
+// Rollbacks record their own events with the reason of the rollback.
+func (r *MigrationRestoreReconciler) recordPhaseEvent(statefulMigration *migrationv1.StatefulMigration, previous *migrationv1.MigrationStatus) {
+	migration := statefulMigration.Status.Migration
+	if migration == nil || (previous != nil && previous.TargetCluster == migration.TargetCluster && previous.Phase == migration.Phase) {
+		return
+	}
+
+	switch migration.Phase {
+	case migrationv1.MigrationPending:
+		r.Recorder.Eventf(statefulMigration, corev1.EventTypeNormal, ReasonMigrationStarted,
+			"Migrating %s to cluster %s", describeWorkloads(statefulMigration), migration.TargetCluster)
+	case migrationv1.MigrationCheckpointing:
+		r.Recorder.Event(statefulMigration, corev1.EventTypeNormal, ReasonFinalCheckpointRequested, migration.Message)
+	case migrationv1.MigrationRestoring:
+		r.Recorder.Event(statefulMigration, corev1.EventTypeNormal, ReasonRestoringPods, migration.Message)
+	case migrationv1.MigrationSwitchingPlacement:
+		r.Recorder.Event(statefulMigration, corev1.EventTypeNormal, ReasonSwitchingPlacement, migration.Message)
+	case migrationv1.MigrationVerifying:
+		r.Recorder.Event(statefulMigration, corev1.EventTypeNormal, ReasonVerifyingPods, migration.Message)
+	case migrationv1.MigrationCompleted:
+		r.Recorder.Event(statefulMigration, corev1.EventTypeNormal, ReasonMigrationCompleted, migration.Message)
+	case migrationv1.MigrationFailed:
+		r.Recorder.Event(statefulMigration, corev1.EventTypeWarning, ReasonMigrationFailed, migration.Message)
+	}
+}
+
+// startRollback records why the migration is rolled back and moves it to the RollingBack phase
+func (r *MigrationRestoreReconciler) startRollback(statefulMigration *migrationv1.StatefulMigration, reason string) {
+	migration := statefulMigration.Status.Migration
+	migration.Phase = migrationv1.MigrationRollingBack
+	migration.RollbackReason = reason
+	migration.Message = fmt.Sprintf("Rolling back: %s", reason)
+	r.Recorder.Event(statefulMigration, corev1.EventTypeWarning, ReasonRollbackStarted, reason)
+}
+
+// rollBack moves the workload back to the source clusters and removes the restored pods from the target cluster.
+// With the PreviousCheckpoint strategy, the source pods are restored from the checkpoint preceding the final one
+// before the placement is put back, so that the workload adopts them instead of starting new pods.
+func (r *MigrationRestoreReconciler) rollBack(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) error {
+	log := logf.FromContext(ctx)
+	migration := statefulMigration.Status.Migration
+
+	// The source pods only need restoring if the workload was moved off the source clusters
+	switched := len(migration.SourcePlacements) > 0
+	if switched && rollbackStrategy(statefulMigration) == migrationv1.RollbackPreviousCheckpoint {
+		if migration.RollbackRestores == nil {
+			if err := r.createRollbackRestores(ctx, statefulMigration); err != nil {
+				return err
+			}
+			migration.Message = fmt.Sprintf("Rolling back: restoring %d pod(s) on the source clusters", len(migration.RollbackRestores))
+			return nil
+		}
+
+		// A restore that failed leaves its pod to be recreated from the original images
+		for _, ref := range migration.RollbackRestores {
+			restore, err := r.MemberClusterClient.GetCheckpointRestoreFromCluster(ctx, ref.Cluster, ref.Namespace, ref.Name)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			if err != nil || (restore.Status.Phase != migrationv1.CheckpointRestoreRestored && restore.Status.Phase != migrationv1.CheckpointRestoreFailed) {
+				migration.Message = fmt.Sprintf("Rolling back: waiting for pod %s to be restored on cluster %s", ref.PodName, ref.Cluster)
+				return nil
+			}
+		}
+	}
+
+	for _, placement := range migration.SourcePlacements {
+		policy := &karmadav1alpha1.PropagationPolicy{}
+		if err := r.KarmadaClient.Get(ctx, types.NamespacedName{Name: placement.Name, Namespace: placement.Namespace}, policy); err != nil {
+			return fmt.Errorf("failed to get PropagationPolicy %s/%s: %w", placement.Namespace, placement.Name, err)
+		}
+		policy.Spec.Placement.ClusterAffinity = nil
+		if len(placement.ClusterNames) > 0 {
+			policy.Spec.Placement.ClusterAffinity = &karmadav1alpha1.ClusterAffinity{
+				ClusterNames: placement.ClusterNames,
+			}
+		}
+		if err := r.KarmadaClient.Update(ctx, policy); err != nil {
+			return fmt.Errorf("failed to update PropagationPolicy %s/%s: %w", policy.Namespace, policy.Name, err)
+		}
+		log.Info("Moved workload placement back", "policy", policy.Name, "clusters", placement.ClusterNames)
+	}
+
+	// Remove the restored pods from the target cluster
+	if err := r.deleteCheckpointRestores(ctx, migration.Restores); err != nil {
+		return err
+	}
+
+	now := metav1.NewTime(r.clock())
+	migration.Phase = migrationv1.MigrationRolledBack
+	migration.CompletionTime = &now
+	migration.Message = fmt.Sprintf("Rolled back %s from cluster %s: %s", describeWorkloads(statefulMigration), migration.TargetCluster, migration.RollbackReason)
+	r.Recorder.Event(statefulMigration, corev1.EventTypeWarning, ReasonRolledBack, migration.Message)
+	return nil
+}
+
+// createRollbackRestores creates a CheckpointRestore on the source cluster of every CheckpointBackup,
+// restoring the pod from the last checkpoint taken before the final checkpoint of the migration
+func (r *MigrationRestoreReconciler) createRollbackRestores(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) error {
+	migration := statefulMigration.Status.Migration
+
+	backups, err := r.listCheckpointBackups(ctx, statefulMigration)
+	if err != nil {
+		return fmt.Errorf("failed to list CheckpointBackups: %w", err)
+	}
+
+	statefulSets := make(map[migrationv1.ResourceRef]*appsv1.StatefulSet)
+	restorePoint := &migrationv1.RestorePoint{Type: migrationv1.RestorePointBeforeTime}
+	if migration.CheckpointRequestTime != nil {
+		before := metav1.NewTime(migration.CheckpointRequestTime.Add(-time.Second))
+		restorePoint.Time = &before
+	} else {
+		restorePoint.Type = migrationv1.RestorePointLatest
+	}
+
+	refs := []migrationv1.MigrationRestoreRef{}
+	for i := range backups {
+		backup := &backups[i]
+		cluster := backup.Labels["target-cluster"]
+
+		namespace := backup.Spec.PodRef.Namespace
+		if namespace == "" {
+			namespace = backup.Namespace
+		}
+
+		template, err := r.getPodTemplate(ctx, backup)
+		if err != nil {
+			return fmt.Errorf("failed to get pod template of %s: %w", backup.Spec.PodRef.Name, err)
+		}
+
+		statefulSet, found := statefulSets[backup.Spec.ResourceRef]
+		if !found {
+			if statefulSet, err = r.getStatefulSet(ctx, backup.Spec.ResourceRef); err != nil {
+				return err
+			}
+			statefulSets[backup.Spec.ResourceRef] = statefulSet
+		}
+
+		var identity *migrationv1.StatefulSetIdentity
+		if statefulSet != nil {
+			if identity, err = r.getStatefulSetIdentity(ctx, statefulSet, backup); err != nil {
+				return err
+			}
+		}
+		var deployment *migrationv1.DeploymentIdentity
+		if isDeploymentResource(backup.Spec.ResourceRef) {
+			if deployment, err = r.getDeploymentIdentity(ctx, backup); err != nil {
+				return err
+			}
+		}
+
+		// The images are resolved from the backup history on the source cluster
+		restore := &migrationv1.CheckpointRestore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-rollback", backup.Name),
+				Namespace: namespace,
+				Labels: map[string]string{
+					"stateful-migration":           statefulMigration.Name,
+					"stateful-migration-namespace": statefulMigration.Namespace,
+					"target-cluster":               cluster,
+					"target-pod":                   backup.Spec.PodRef.Name,
+				},
+			},
+			Spec: migrationv1.CheckpointRestoreSpec{
+				BackupRef:    migrationv1.BackupRef{Name: backup.Name},
+				PodName:      backup.Spec.PodRef.Name,
+				RestorePoint: restorePoint,
+				PodTemplate:  template,
+				StatefulSet:  identity,
+				Deployment:   deployment,
+			},
+		}
+		if err := r.applyCheckpointRestore(ctx, restore, cluster); err != nil {
+			return err
+		}
+
+		ref := migrationv1.MigrationRestoreRef{
+			Name:       restore.Name,
+			Namespace:  restore.Namespace,
+			BackupName: backup.Name,
+			Cluster:    cluster,
+			PodName:    restore.Spec.PodName,
+		}
+		if identity != nil {
+			ref.Ordinal = &identity.Ordinal
+		}
+		refs = append(refs, ref)
+	}
+
+	migration.RollbackRestores = refs
+	return nil
+}
+
+// findWorkloadPropagationPolicies finds the PropagationPolicies that propagate the enrolled workloads.
+// Workloads propagated by the same policy share it.
+func (r *MigrationRestoreReconciler) findWorkloadPropagationPolicies(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) ([]*karmadav1alpha1.PropagationPolicy, error) {
+	var policies []*karmadav1alpha1.PropagationPolicy
+	for _, resourceRef := range enrolledWorkloads(statefulMigration) {
+		policy, err := r.findWorkloadPropagationPolicy(ctx, resourceRef)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(policies, func(found *karmadav1alpha1.PropagationPolicy) bool {
+			return found.Namespace == policy.Namespace && found.Name == policy.Name
+		}) {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+// findWorkloadPropagationPolicy finds the PropagationPolicy that propagates the workload, using the
+// annotations Karmada sets on the resource template or else the policies' resource selectors
+func (r *MigrationRestoreReconciler) findWorkloadPropagationPolicy(ctx context.Context, resourceRef migrationv1.ResourceRef) (*karmadav1alpha1.PropagationPolicy, error) {
+
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion(resourceRef.APIVersion)
+	workload.SetKind(resourceRef.Kind)
+	if err := r.KarmadaClient.Get(ctx, types.NamespacedName{Name: resourceRef.Name, Namespace: resourceRef.Namespace}, workload); err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", resourceRef.Kind, resourceRef.Name, err)
+	}
+
+	policy := &karmadav1alpha1.PropagationPolicy{}
+	annotations := workload.GetAnnotations()
+	if name := annotations[karmadav1alpha1.PropagationPolicyNameAnnotation]; name != "" {
+		namespace := annotations[karmadav1alpha1.PropagationPolicyNamespaceAnnotation]
+		if namespace == "" {
+			namespace = workload.GetNamespace()
+		}
+		if err := r.KarmadaClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, policy); err != nil {
+			return nil, fmt.Errorf("failed to get PropagationPolicy %s/%s: %w", namespace, name, err)
+		}
+		return policy, nil
+	}
+
+	var policies karmadav1alpha1.PropagationPolicyList
+	if err := r.KarmadaClient.List(ctx, &policies, client.InNamespace(workload.GetNamespace())); err != nil {
+		return nil, fmt.Errorf("failed to list PropagationPolicies: %w", err)
+	}
+	for i := range policies.Items {
+		for _, selector := range policies.Items[i].Spec.ResourceSelectors {
+			if resourceSelectorMatches(selector, workload) {
+				return &policies.Items[i], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no PropagationPolicy propagates %s %s", resourceRef.Kind, resourceRef.Name)
+}
+
+// resourceSelectorMatches reports whether a PropagationPolicy resource selector selects the object
+func resourceSelectorMatches(selector karmadav1alpha1.ResourceSelector, obj *unstructured.Unstructured) bool {
+	if selector.APIVersion != obj.GetAPIVersion() || selector.Kind != obj.GetKind() {
+		return false
+	}
+	if selector.Namespace != "" && selector.Namespace != obj.GetNamespace() {
+		return false
+	}
+	if selector.Name != "" {
+		return selector.Name == obj.GetName()
+	}
+	if selector.LabelSelector == nil {
+		return true
+	}
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector.LabelSelector)
+	if err != nil {
+		return false
+	}
+	return labelSelector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// reconcileDelete removes the CheckpointRestores created for the migration
+func (r *MigrationRestoreReconciler) reconcileDelete(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(statefulMigration, MigrationRestoreFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if migration := statefulMigration.Status.Migration; migration != nil {
+		if err := r.deleteCheckpointRestores(ctx, append(migration.Restores, migration.RollbackRestores...)); err != nil {
+			log.Error(err, "Failed to delete CheckpointRestores")
+			return ctrl.Result{}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(statefulMigration, MigrationRestoreFinalizer)
+	if err := r.Update(ctx, statefulMigration); err != nil {
+		log.Error(err, "Failed to remove finalizer")
+		return ctrl.Result{}, err
+	}
 
 	return ctrl.Result{}, nil
 }
 
+// deleteCheckpointRestores deletes the CheckpointRestores and the PropagationPolicies that propagate them
+func (r *MigrationRestoreReconciler) deleteCheckpointRestores(ctx context.Context, refs []migrationv1.MigrationRestoreRef) error {
+	for _, ref := range refs {
+		restore := &migrationv1.CheckpointRestore{
+			ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace},
+		}
+		if err := r.Delete(ctx, restore); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete CheckpointRestore %s: %w", ref.Name, err)
+		}
+
+		if err := deleteCheckpointPropagationPolicy(ctx, r.KarmadaClient, restore); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listCheckpointBackups lists the CheckpointBackups created for the StatefulMigration
+func (r *MigrationRestoreReconciler) listCheckpointBackups(ctx context.Context, statefulMigration *migrationv1.StatefulMigration) ([]migrationv1.CheckpointBackup, error) {
+	var backupList migrationv1.CheckpointBackupList
+	if err := r.List(ctx, &backupList, &client.ListOptions{
+		Namespace: statefulMigration.Namespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			"stateful-migration": statefulMigration.Name,
+		}),
+	}); err != nil {
+		return nil, err
+	}
+	return backupList.Items, nil
+}
+
+// failMigration marks the migration as failed
+func (r *MigrationRestoreReconciler) failMigration(migration *migrationv1.MigrationStatus, message string) {
+	now := metav1.NewTime(r.clock())
+	migration.Phase = migrationv1.MigrationFailed
+	migration.Message = message
+	migration.CompletionTime = &now
+}
+
+// ensureKarmadaClients uses the current Karmada and member cluster clients, which are rebuilt when the credentials rotate
+func (r *MigrationRestoreReconciler) ensureKarmadaClients() {
+	if r.Karmada != nil {
+		r.KarmadaClient, r.MemberClusterClient = r.Karmada.Clients()
+	}
+}
+
+// healthTimeout returns how long the restored pods of the migration have to become ready
+func healthTimeout(statefulMigration *migrationv1.StatefulMigration) time.Duration {
+	if rollback := statefulMigration.Spec.Rollback; rollback != nil && rollback.HealthTimeout != nil {
+		return rollback.HealthTimeout.Duration
+	}
+	return DefaultMigrationHealthTimeout
+}
+
+// rollbackStrategy returns what the source clusters run after a rollback of the migration
+func rollbackStrategy(statefulMigration *migrationv1.StatefulMigration) migrationv1.RollbackStrategy {
+	if rollback := statefulMigration.Spec.Rollback; rollback != nil && rollback.Strategy != "" {
+		return rollback.Strategy
+	}
+	return migrationv1.RollbackOriginalImages
+}
+
+// isMigrationFinished reports whether the migration phase is final
+func isMigrationFinished(phase migrationv1.MigrationPhase) bool {
+	return phase == migrationv1.MigrationCompleted || phase == migrationv1.MigrationRolledBack || phase == migrationv1.MigrationFailed
+}
+
+// clock returns the current time
+func (r *MigrationRestoreReconciler) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// isPodReady reports whether the pod has the Ready condition
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *MigrationRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -17,16 +17,654 @@ limitations under the License.
 package controller
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	restfake "k8s.io/client-go/rest/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
 )
 
 var _ = Describe("MigrationRestore Controller", func() {
 	Context("When reconciling a resource", func() {
+		const resourceName = "test-migration"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		statefulMigration := &migrationv1.StatefulMigration{}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind StatefulMigration")
+			err := k8sClient.Get(ctx, typeNamespacedName, statefulMigration)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &migrationv1.StatefulMigration{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: migrationv1.StatefulMigrationSpec{
+						ResourceRef: migrationv1.ResourceRef{
+							APIVersion: "apps/v1",
+							Kind:       "StatefulSet",
+							Namespace:  "default",
+							Name:       "test-sts",
+						},
+						SourceClusters: []string{"member1"},
+						Registry: migrationv1.Registry{
+							URL:        "registry.example.com",
+							Repository: "checkpoints",
+						},
+						Schedule: "*/5 * * * *",
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &migrationv1.StatefulMigration{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+
+			By("Cleanup the specific resource instance StatefulMigration")
+			controllerutil.RemoveFinalizer(resource, MigrationRestoreFinalizer)
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should do nothing without a target cluster", func() {
+			controllerReconciler := &MigrationRestoreReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, statefulMigration)).To(Succeed())
+			Expect(statefulMigration.Status.Migration).To(BeNil())
+			Expect(statefulMigration.Finalizers).NotTo(ContainElement(MigrationRestoreFinalizer))
+		})
+
+		It("should start a migration and wait for Karmada", func() {
+			Expect(k8sClient.Get(ctx, typeNamespacedName, statefulMigration)).To(Succeed())
+			statefulMigration.Spec.TargetCluster = "member2"
+			Expect(k8sClient.Update(ctx, statefulMigration)).To(Succeed())
+
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &MigrationRestoreReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(migrationPollInterval))
+			Expect(recorder.Events).To(Receive(And(HavePrefix("Normal "+ReasonMigrationStarted), HaveSuffix("to cluster member2"))))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, statefulMigration)).To(Succeed())
+			Expect(statefulMigration.Finalizers).To(ContainElement(MigrationRestoreFinalizer))
+			Expect(statefulMigration.Status.Migration).NotTo(BeNil())
+			Expect(statefulMigration.Status.Migration.TargetCluster).To(Equal("member2"))
+			Expect(statefulMigration.Status.Migration.Phase).To(Equal(migrationv1.MigrationPending))
+			Expect(statefulMigration.Status.Migration.StartTime).NotTo(BeNil())
+		})
+
+		It("should fail a migration to one of the source clusters", func() {
+			Expect(k8sClient.Get(ctx, typeNamespacedName, statefulMigration)).To(Succeed())
+			statefulMigration.Spec.TargetCluster = "member1"
+			Expect(k8sClient.Update(ctx, statefulMigration)).To(Succeed())
+
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &MigrationRestoreReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(recorder.Events).To(Receive(Equal("Warning " + ReasonMigrationFailed + " Target cluster member1 is one of the source clusters")))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, statefulMigration)).To(Succeed())
+			Expect(statefulMigration.Status.Migration.Phase).To(Equal(migrationv1.MigrationFailed))
+			Expect(statefulMigration.Status.Migration.CompletionTime).NotTo(BeNil())
+		})
+	})
+
+	Context("When matching PropagationPolicy resource selectors", func() {
+		workload := &unstructured.Unstructured{}
+		workload.SetAPIVersion("apps/v1")
+		workload.SetKind("StatefulSet")
+		workload.SetNamespace("default")
+		workload.SetName("web")
+		workload.SetLabels(map[string]string{"app": "web"})
+
+		It("should match selectors by name", func() {
+			Expect(resourceSelectorMatches(karmadav1alpha1.ResourceSelector{
+				APIVersion: "apps/v1", Kind: "StatefulSet", Name: "web",
+			}, workload)).To(BeTrue())
+			Expect(resourceSelectorMatches(karmadav1alpha1.ResourceSelector{
+				APIVersion: "apps/v1", Kind: "StatefulSet", Name: "db",
+			}, workload)).To(BeFalse())
+			Expect(resourceSelectorMatches(karmadav1alpha1.ResourceSelector{
+				APIVersion: "apps/v1", Kind: "Deployment", Name: "web",
+			}, workload)).To(BeFalse())
+		})
+
+		It("should match selectors by label", func() {
+			Expect(resourceSelectorMatches(karmadav1alpha1.ResourceSelector{
+				APIVersion:    "apps/v1",
+				Kind:          "StatefulSet",
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			}, workload)).To(BeTrue())
+			Expect(resourceSelectorMatches(karmadav1alpha1.ResourceSelector{
+				APIVersion:    "apps/v1",
+				Kind:          "StatefulSet",
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			}, workload)).To(BeFalse())
+		})
+	})
+
+	Context("When restoring the pods of a StatefulSet", func() {
+		backup := func(name, podName string) *migrationv1.CheckpointBackup {
+			return &migrationv1.CheckpointBackup{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec:       migrationv1.CheckpointBackupSpec{PodRef: migrationv1.PodRef{Name: podName}},
+			}
+		}
+		backups := []*migrationv1.CheckpointBackup{
+			backup("web-2-backup", "web-2"),
+			backup("web-0-backup", "web-0"),
+			backup("other-0-backup", "other-0"),
+			backup("web-1-backup", "web-1"),
+		}
+
+		It("should parse pod ordinals", func() {
+			ordinal, ok := statefulSetOrdinal("web", "web-12")
+			Expect(ok).To(BeTrue())
+			Expect(ordinal).To(Equal(int32(12)))
+
+			for _, podName := range []string{"web", "web-", "web-01", "web--1", "web-a", "webapp-0"} {
+				_, ok := statefulSetOrdinal("web", podName)
+				Expect(ok).To(BeFalse(), podName)
+			}
+		})
+
+		It("should restore one ordinal at a time with OrderedReady", func() {
+			statefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+
+			next := nextStatefulSetRestores(statefulSet, backups)
+			Expect(next).To(HaveLen(1))
+			Expect(next[0].Name).To(Equal("web-0-backup"))
+		})
+
+		It("should restore every ordinal in order with Parallel", func() {
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "web"},
+				Spec:       appsv1.StatefulSetSpec{PodManagementPolicy: appsv1.ParallelPodManagement},
+			}
+
+			var names []string
+			for _, backup := range nextStatefulSetRestores(statefulSet, backups) {
+				names = append(names, backup.Name)
+			}
+			Expect(names).To(Equal([]string{"web-0-backup", "web-1-backup", "web-2-backup"}))
+		})
+	})
+
+	Context("When checking the pre-flight result before a migration", func() {
+		newMigration := func(status metav1.ConditionStatus, generation int64) *migrationv1.StatefulMigration {
+			statefulMigration := &migrationv1.StatefulMigration{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       migrationv1.StatefulMigrationSpec{SourceClusters: []string{"member1"}, TargetCluster: "member2"},
+				Status: migrationv1.StatefulMigrationStatus{
+					Preflight: []migrationv1.ClusterPreflight{{Name: "member1"}, {Name: "member2"}},
+					Migration: &migrationv1.MigrationStatus{TargetCluster: "member2", Phase: migrationv1.MigrationPending},
+				},
+			}
+			statefulMigration.Status.Conditions = []metav1.Condition{{
+				Type:               migrationv1.StatefulMigrationConditionCompatible,
+				Status:             status,
+				ObservedGeneration: generation,
+				Reason:             ReasonPreflightFailed,
+				Message:            "target cluster member2: no amd64 nodes to restore checkpoints from cluster member1",
+			}}
+			return statefulMigration
+		}
+
+		It("should wait for checks of the current generation", func() {
+			statefulMigration := newMigration(metav1.ConditionTrue, 1)
+			Expect((&MigrationRestoreReconciler{}).checkPreflight(statefulMigration)).To(BeFalse())
+			Expect(statefulMigration.Status.Migration.Phase).To(Equal(migrationv1.MigrationPending))
+		})
+
+		It("should fail the migration when the checks failed", func() {
+			statefulMigration := newMigration(metav1.ConditionFalse, 2)
+			Expect((&MigrationRestoreReconciler{}).checkPreflight(statefulMigration)).To(BeFalse())
+			Expect(statefulMigration.Status.Migration.Phase).To(Equal(migrationv1.MigrationFailed))
+			Expect(statefulMigration.Status.Migration.Message).To(ContainSubstring("no amd64 nodes"))
+		})
+
+		It("should proceed when the checks passed", func() {
+			Expect((&MigrationRestoreReconciler{}).checkPreflight(newMigration(metav1.ConditionTrue, 2))).To(BeTrue())
+		})
+	})
+
+	Context("When the restored pods are not healthy", func() {
+		ctx := context.Background()
+
+		It("should default the health timeout", func() {
+			statefulMigration := &migrationv1.StatefulMigration{}
+			Expect(healthTimeout(statefulMigration)).To(Equal(DefaultMigrationHealthTimeout))
+			Expect(rollbackStrategy(statefulMigration)).To(Equal(migrationv1.RollbackOriginalImages))
+
+			statefulMigration.Spec.Rollback = &migrationv1.RollbackPolicy{
+				HealthTimeout: &metav1.Duration{Duration: 2 * time.Minute},
+				Strategy:      migrationv1.RollbackPreviousCheckpoint,
+			}
+			Expect(healthTimeout(statefulMigration)).To(Equal(2 * time.Minute))
+			Expect(rollbackStrategy(statefulMigration)).To(Equal(migrationv1.RollbackPreviousCheckpoint))
+		})
+
+		It("should roll back a migration before the workload was moved", func() {
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &MigrationRestoreReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+
+			statefulMigration := &migrationv1.StatefulMigration{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-rollback", Namespace: "default"},
+				Status: migrationv1.StatefulMigrationStatus{
+					Migration: &migrationv1.MigrationStatus{
+						TargetCluster: "member2",
+						Phase:         migrationv1.MigrationRestoring,
+						Restores: []migrationv1.MigrationRestoreRef{{
+							Name: "web-0-backup-restore", Namespace: "default", BackupName: "web-0-backup", PodName: "web-0",
+						}},
+					},
+				},
+			}
+
+			controllerReconciler.startRollback(statefulMigration, "Restored pods did not become ready on cluster member2 within 10m0s")
+			Expect(statefulMigration.Status.Migration.Phase).To(Equal(migrationv1.MigrationRollingBack))
+			Expect(recorder.Events).To(Receive(ContainSubstring(ReasonRollbackStarted)))
+
+			Expect(controllerReconciler.rollBack(ctx, statefulMigration)).To(Succeed())
+			migration := statefulMigration.Status.Migration
+			Expect(migration.Phase).To(Equal(migrationv1.MigrationRolledBack))
+			Expect(migration.RollbackReason).To(ContainSubstring("did not become ready"))
+			Expect(migration.CompletionTime).NotTo(BeNil())
+			Expect(recorder.Events).To(Receive(ContainSubstring(ReasonRolledBack)))
+		})
+	})
+
+	Context("When migrating a Deployment", func() {
+		const (
+			migrationName = "api-migration"
+			backupName    = "api-7c9d5-x2kqp-member1"
+			restoreName   = backupName + "-restore"
+			podName       = "api-7c9d5-x2kqp"
+		)
+
+		ctx := context.Background()
+		migrationKey := types.NamespacedName{Name: migrationName, Namespace: "default"}
+		start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+		var (
+			karmadaClient *KarmadaClient
+			// memberObjects serves the objects of the member clusters behind the Karmada cluster proxy,
+			// keyed by <cluster>/proxy/<path>
+			memberObjects map[string]runtime.Object
+		)
+
+		template := corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "api:1.0"}}},
+		}
+
+		memberProxy := func(req *http.Request) (*http.Response, error) {
+			respond := func(code int, obj runtime.Object) (*http.Response, error) {
+				body, err := json.Marshal(obj)
+				if err != nil {
+					return nil, err
+				}
+				header := http.Header{}
+				header.Set("Content-Type", runtime.ContentTypeJSON)
+				return &http.Response{StatusCode: code, Header: header, Body: io.NopCloser(bytes.NewReader(body))}, nil
+			}
+
+			// The CRDs are applied to the target cluster before restoring
+			if req.Method == http.MethodPatch {
+				return respond(http.StatusOK, &metav1.Status{TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}, Status: metav1.StatusSuccess})
+			}
+			_, path, _ := strings.Cut(req.URL.Path, "/clusters/")
+			if obj, found := memberObjects[path]; found {
+				return respond(http.StatusOK, obj)
+			}
+			return respond(http.StatusNotFound, &metav1.Status{
+				TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+				Status:   metav1.StatusFailure,
+				Reason:   metav1.StatusReasonNotFound,
+				Code:     http.StatusNotFound,
+			})
+		}
+
+		BeforeEach(func() {
+			By("creating the Deployment and its PropagationPolicy on Karmada")
+			karmadaScheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(karmadaScheme)).To(Succeed())
+			Expect(karmadav1alpha1.AddToScheme(karmadaScheme)).To(Succeed())
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "api",
+					Namespace: "default",
+					Annotations: map[string]string{
+						karmadav1alpha1.PropagationPolicyNameAnnotation:      "api-pp",
+						karmadav1alpha1.PropagationPolicyNamespaceAnnotation: "default",
+					},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: ptr.To(int32(1)),
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+					Template: template,
+				},
+			}
+			policy := &karmadav1alpha1.PropagationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-pp", Namespace: "default"},
+				Spec: karmadav1alpha1.PropagationSpec{
+					ResourceSelectors: []karmadav1alpha1.ResourceSelector{{APIVersion: "apps/v1", Kind: "Deployment", Name: "api"}},
+					Placement: karmadav1alpha1.Placement{
+						ClusterAffinity: &karmadav1alpha1.ClusterAffinity{ClusterNames: []string{"member1"}},
+					},
+				},
+			}
+			karmadaClient = &KarmadaClient{
+				Client: fake.NewClientBuilder().WithScheme(karmadaScheme).WithObjects(deployment.DeepCopy(), policy).Build(),
+				restClient: &restfake.RESTClient{
+					NegotiatedSerializer: clientgoscheme.Codecs.WithoutConversion(),
+					Client:               restfake.CreateHTTPClient(memberProxy),
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+
+			By("creating the StatefulMigration with passing pre-flight checks")
+			statefulMigration := &migrationv1.StatefulMigration{
+				ObjectMeta: metav1.ObjectMeta{Name: migrationName, Namespace: "default"},
+				Spec: migrationv1.StatefulMigrationSpec{
+					ResourceRef:    migrationv1.ResourceRef{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "api"},
+					SourceClusters: []string{"member1"},
+					TargetCluster:  "member2",
+					Registry:       migrationv1.Registry{URL: "registry.example.com", Repository: "checkpoints"},
+					Schedule:       "*/5 * * * *",
+				},
+			}
+			Expect(k8sClient.Create(ctx, statefulMigration)).To(Succeed())
+			statefulMigration.Status.Preflight = []migrationv1.ClusterPreflight{{Name: "member1"}, {Name: "member2"}}
+			statefulMigration.Status.Conditions = []metav1.Condition{{
+				Type:               migrationv1.StatefulMigrationConditionCompatible,
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: statefulMigration.Generation,
+				Reason:             ReasonPreflightPassed,
+				Message:            "All clusters passed the pre-flight checks",
+				LastTransitionTime: metav1.NewTime(start),
+			}}
+			Expect(k8sClient.Status().Update(ctx, statefulMigration)).To(Succeed())
+
+			By("creating the CheckpointBackup of the Deployment's pod on the source cluster")
+			backup := &migrationv1.CheckpointBackup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      backupName,
+					Namespace: "default",
+					Labels:    map[string]string{"stateful-migration": migrationName, "target-cluster": "member1"},
+				},
+				Spec: migrationv1.CheckpointBackupSpec{
+					Schedule:    "*/5 * * * *",
+					PodRef:      migrationv1.PodRef{Namespace: "default", Name: podName},
+					ResourceRef: statefulMigration.Spec.ResourceRef,
+					Registry:    statefulMigration.Spec.Registry,
+				},
+			}
+			Expect(k8sClient.Create(ctx, backup)).To(Succeed())
+
+			checkpointTime := metav1.NewTime(start.Add(time.Second))
+			memberBackup := backup.DeepCopy()
+			memberBackup.Status = migrationv1.CheckpointBackupStatus{
+				Phase:              migrationv1.CheckpointBackupSucceeded,
+				LastScheduleTime:   &checkpointTime,
+				LastSuccessfulTime: &checkpointTime,
+				Containers:         []migrationv1.ContainerCheckpoint{{Name: "app", Image: "registry.example.com/checkpoints:api-app-20250101100001"}},
+			}
+			sourcePod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      podName,
+					Namespace: "default",
+					Labels:    map[string]string{"app": "api", appsv1.DefaultDeploymentUniqueLabelKey: "7c9d5"},
+				},
+			}
+			memberObjects = map[string]runtime.Object{
+				"member1/proxy/apis/migration.dcnlab.com/v1/namespaces/default/checkpointbackups/" + backupName: memberBackup,
+				"member1/proxy/api/v1/namespaces/default/pods/" + podName:                                       sourcePod,
+			}
+		})
+
+		AfterEach(func() {
+			statefulMigration := &migrationv1.StatefulMigration{}
+			Expect(k8sClient.Get(ctx, migrationKey, statefulMigration)).To(Succeed())
+			controllerutil.RemoveFinalizer(statefulMigration, MigrationRestoreFinalizer)
+			Expect(k8sClient.Update(ctx, statefulMigration)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, statefulMigration)).To(Succeed())
+
+			for _, obj := range []client.Object{
+				&migrationv1.CheckpointBackup{ObjectMeta: metav1.ObjectMeta{Name: backupName, Namespace: "default"}},
+				&migrationv1.CheckpointRestore{ObjectMeta: metav1.ObjectMeta{Name: restoreName, Namespace: "default"}},
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"}},
+			} {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, obj))).To(Succeed())
+			}
+		})
+
+		It("should hand the restored pod over to the ReplicaSet on the target cluster", func() {
+			controllerReconciler := &MigrationRestoreReconciler{
+				Client:              k8sClient,
+				Scheme:              k8sClient.Scheme(),
+				KarmadaClient:       karmadaClient,
+				MemberClusterClient: &MemberClusterClient{karmadaClient: karmadaClient},
+				Recorder:            record.NewFakeRecorder(100),
+				now:                 func() time.Time { return start },
+			}
+			reconcileMigration := func() *migrationv1.MigrationStatus {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: migrationKey})
+				Expect(err).NotTo(HaveOccurred())
+				statefulMigration := &migrationv1.StatefulMigration{}
+				Expect(k8sClient.Get(ctx, migrationKey, statefulMigration)).To(Succeed())
+				return statefulMigration.Status.Migration
+			}
+
+			By("requesting the final checkpoint")
+			Expect(reconcileMigration().Phase).To(Equal(migrationv1.MigrationCheckpointing))
+
+			By("restoring the pod with the pod-template-hash of the source pod")
+			Expect(reconcileMigration().Phase).To(Equal(migrationv1.MigrationRestoring))
+			restore := &migrationv1.CheckpointRestore{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: restoreName, Namespace: "default"}, restore)).To(Succeed())
+			Expect(restore.Spec.PodName).To(Equal(podName))
+			Expect(restore.Spec.StatefulSet).To(BeNil())
+			Expect(restore.Spec.Deployment).To(Equal(&migrationv1.DeploymentIdentity{Name: "api", PodTemplateHash: "7c9d5"}))
+
+			By("moving the Deployment once the restored pod is ready")
+			restoredPod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      podName,
+					Namespace: "default",
+					Labels:    map[string]string{"app": "api", appsv1.DefaultDeploymentUniqueLabelKey: "7c9d5"},
+				},
+				Status: corev1.PodStatus{
+					Phase:      corev1.PodRunning,
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+				},
+			}
+			memberRestore := restore.DeepCopy()
+			memberRestore.Status.Phase = migrationv1.CheckpointRestoreRestored
+			memberObjects["member2/proxy/apis/migration.dcnlab.com/v1/namespaces/default/checkpointrestores/"+restoreName] = memberRestore
+			memberObjects["member2/proxy/api/v1/namespaces/default/pods/"+podName] = restoredPod
+			Expect(reconcileMigration().Phase).To(Equal(migrationv1.MigrationSwitchingPlacement))
+			Expect(reconcileMigration().Phase).To(Equal(migrationv1.MigrationVerifying))
+
+			policy := &karmadav1alpha1.PropagationPolicy{}
+			Expect(karmadaClient.Get(ctx, types.NamespacedName{Name: "api-pp", Namespace: "default"}, policy)).To(Succeed())
+			Expect(policy.Spec.Placement.ClusterAffinity.ClusterNames).To(Equal([]string{"member2"}))
+
+			By("waiting for the ReplicaSet to adopt the restored pod")
+			migration := reconcileMigration()
+			Expect(migration.Phase).To(Equal(migrationv1.MigrationVerifying))
+			Expect(migration.Message).To(Equal("Waiting for restored pod " + podName + " to be adopted by its ReplicaSet"))
+
+			restoredPod.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Name:       "api-7c9d5",
+				UID:        types.UID("replicaset-uid"),
+				Controller: ptr.To(true),
+			}}
+			migration = reconcileMigration()
+			Expect(migration.Phase).To(Equal(migrationv1.MigrationCompleted))
+			Expect(migration.Message).To(Equal("Deployment api migrated to cluster member2"))
+		})
+
+		It("should wait for the final checkpoint while the pod is not checkpointed", func() {
+			now := start
+			controllerReconciler := &MigrationRestoreReconciler{
+				Client:              k8sClient,
+				Scheme:              k8sClient.Scheme(),
+				KarmadaClient:       karmadaClient,
+				MemberClusterClient: &MemberClusterClient{karmadaClient: karmadaClient},
+				Recorder:            record.NewFakeRecorder(100),
+				now:                 func() time.Time { return now },
+			}
+			reconcileMigration := func() *migrationv1.MigrationStatus {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: migrationKey})
+				Expect(err).NotTo(HaveOccurred())
+				statefulMigration := &migrationv1.StatefulMigration{}
+				Expect(k8sClient.Get(ctx, migrationKey, statefulMigration)).To(Succeed())
+				return statefulMigration.Status.Migration
+			}
+			Expect(reconcileMigration().Phase).To(Equal(migrationv1.MigrationCheckpointing))
+			waiting := "Waiting for the final checkpoint of pod " + podName + " on cluster member1"
+
+			By("waiting while the requested run has not been scheduled")
+			memberBackup := memberObjects["member1/proxy/apis/migration.dcnlab.com/v1/namespaces/default/checkpointbackups/"+backupName].(*migrationv1.CheckpointBackup)
+			served := *memberBackup.Status.LastScheduleTime
+			earlier := metav1.NewTime(start.Add(-time.Minute))
+			memberBackup.Status.LastScheduleTime = &earlier
+			migration := reconcileMigration()
+			Expect(migration.Phase).To(Equal(migrationv1.MigrationCheckpointing))
+			Expect(migration.Message).To(Equal(waiting))
+
+			By("waiting while the pod is not running")
+			memberBackup.Status.LastScheduleTime = &served
+			memberBackup.Status.Phase = migrationv1.CheckpointBackupPending
+			memberBackup.Status.Conditions = []metav1.Condition{{
+				Type:    migrationv1.CheckpointBackupConditionReady,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonPodNotRunning,
+				Message: "Pod " + podName + " is not running (phase Pending)",
+			}}
+			now = start.Add(FinalCheckpointTimeout)
+			migration = reconcileMigration()
+			Expect(migration.Phase).To(Equal(migrationv1.MigrationCheckpointing))
+			Expect(migration.Message).To(Equal(waiting))
+
+			By("failing once the pod is still not running after the timeout")
+			now = start.Add(FinalCheckpointTimeout + time.Second)
+			migration = reconcileMigration()
+			Expect(migration.Phase).To(Equal(migrationv1.MigrationFailed))
+			Expect(migration.Message).To(Equal("Final checkpoint of pod " + podName + " on cluster member1 did not complete within 10m0s: Pod " + podName + " is not running (phase Pending)"))
+		})
+
+		It("should restore a final checkpoint completed by a member cluster whose clock is behind", func() {
+			controllerReconciler := &MigrationRestoreReconciler{
+				Client:              k8sClient,
+				Scheme:              k8sClient.Scheme(),
+				KarmadaClient:       karmadaClient,
+				MemberClusterClient: &MemberClusterClient{karmadaClient: karmadaClient},
+				Recorder:            record.NewFakeRecorder(100),
+				now:                 func() time.Time { return start },
+			}
+			reconcileMigration := func() *migrationv1.MigrationStatus {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: migrationKey})
+				Expect(err).NotTo(HaveOccurred())
+				statefulMigration := &migrationv1.StatefulMigration{}
+				Expect(k8sClient.Get(ctx, migrationKey, statefulMigration)).To(Succeed())
+				return statefulMigration.Status.Migration
+			}
+			Expect(reconcileMigration().Phase).To(Equal(migrationv1.MigrationCheckpointing))
+
+			By("completing the requested run before the request time of the control plane")
+			memberBackup := memberObjects["member1/proxy/apis/migration.dcnlab.com/v1/namespaces/default/checkpointbackups/"+backupName].(*migrationv1.CheckpointBackup)
+			scheduled := metav1.NewTime(start)
+			completed := metav1.NewTime(start.Add(-45 * time.Second))
+			memberBackup.Status.LastScheduleTime = &scheduled
+			memberBackup.Status.LastSuccessfulTime = &completed
+			Expect(reconcileMigration().Phase).To(Equal(migrationv1.MigrationRestoring))
+		})
+
+		It("should not migrate workloads that cannot adopt their restored pods", func() {
+			statefulMigration := &migrationv1.StatefulMigration{}
+			Expect(k8sClient.Get(ctx, migrationKey, statefulMigration)).To(Succeed())
+			statefulMigration.Spec.ResourceRef = migrationv1.ResourceRef{APIVersion: "apps/v1", Kind: "DaemonSet", Namespace: "default", Name: "agent"}
+			Expect(k8sClient.Update(ctx, statefulMigration)).To(Succeed())
 
-		It("should successfully reconcile the resource", func() {
+			controllerReconciler := &MigrationRestoreReconciler{
+				Client:              k8sClient,
+				Scheme:              k8sClient.Scheme(),
+				KarmadaClient:       karmadaClient,
+				MemberClusterClient: &MemberClusterClient{karmadaClient: karmadaClient},
+				Recorder:            record.NewFakeRecorder(100),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: migrationKey})
+			Expect(err).NotTo(HaveOccurred())
 
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+			Expect(k8sClient.Get(ctx, migrationKey, statefulMigration)).To(Succeed())
+			Expect(statefulMigration.Status.Migration.Phase).To(Equal(migrationv1.MigrationFailed))
+			Expect(statefulMigration.Status.Migration.Message).To(HavePrefix("DaemonSet agent cannot be migrated"))
 		})
 	})
 })
@@ -0,0 +1,298 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+const (
+	// CRIUVersionAnnotation is set on nodes to the version of CRIU installed for the container runtime.
+	// Kubernetes does not report it, so nodes without the annotation have an unknown CRIU version.
+	CRIUVersionAnnotation = "migration.dcnlab.com/criu-version"
+
+	// containerCheckpointFeatureGate is the kubelet feature gate enabling the checkpoint API
+	containerCheckpointFeatureGate = "ContainerCheckpoint"
+
+	// preflightInterval is how long the result of the pre-flight checks of a cluster is reused
+	preflightInterval = 10 * time.Minute
+)
+
+// Reasons used in the StatefulMigration Compatible condition
+const (
+	ReasonPreflightPassed = "PreflightPassed"
+	ReasonPreflightFailed = "PreflightFailed"
+)
+
+// minimumRuntimeVersions are the oldest versions of the container runtimes that checkpoint
+// containers through the kubelet and restore them from checkpoint images
+var minimumRuntimeVersions = map[string]string{
+	"cri-o":      "1.25.0",
+	"containerd": "2.0.0",
+}
+
+// inspectCluster runs the pre-flight checks of a member cluster: it reads the architecture, kernel,
+// container runtime and CRIU version of the ready nodes and whether their kubelet allows checkpoints.
+// A node whose kubelet cannot be reached is reported as unreachable rather than failing the inspection.
+func inspectCluster(ctx context.Context, memberClient *MemberClusterClient, cluster string, now time.Time) (*migrationv1.ClusterPreflight, error) {
+	log := logf.FromContext(ctx)
+
+	nodeList, err := memberClient.ListNodesFromCluster(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []corev1.Node
+	checkpointEnabled := make(map[string]bool)
+	for _, node := range nodeList.Items {
+		if !isNodeReady(&node) {
+			continue
+		}
+		nodes = append(nodes, node)
+
+		metrics, err := memberClient.GetKubeletMetricsFromCluster(ctx, cluster, node.Name)
+		if err != nil {
+			log.Error(err, "Failed to inspect the kubelet of node", "cluster", cluster, "node", node.Name)
+			continue
+		}
+		checkpointEnabled[node.Name] = kubeletFeatureEnabled(metrics, containerCheckpointFeatureGate)
+	}
+
+	preflight := summarizeNodes(cluster, nodes, checkpointEnabled)
+	checkTime := metav1.NewTime(now)
+	preflight.CheckTime = &checkTime
+	return preflight, nil
+}
+
+// summarizeNodes reports the capabilities of a cluster from its ready nodes. Versions are the
+// oldest found, since a pod can land on any node. Nodes missing from checkpointEnabled could not
+// be inspected and are reported as unreachable.
+func summarizeNodes(cluster string, nodes []corev1.Node, checkpointEnabled map[string]bool) *migrationv1.ClusterPreflight {
+	preflight := &migrationv1.ClusterPreflight{
+		Name:              cluster,
+		CheckpointEnabled: len(nodes) > 0,
+	}
+
+	var runtimes []string
+	for _, node := range nodes {
+		info := node.Status.NodeInfo
+
+		if !slices.Contains(preflight.Architectures, info.Architecture) {
+			preflight.Architectures = append(preflight.Architectures, info.Architecture)
+		}
+		if preflight.KernelVersion == "" || compareVersions(info.KernelVersion, preflight.KernelVersion) < 0 {
+			preflight.KernelVersion = info.KernelVersion
+		}
+
+		// The runtime version is reported as <runtime>://<version>
+		runtime, version, _ := strings.Cut(info.ContainerRuntimeVersion, "://")
+		if !slices.Contains(runtimes, runtime) {
+			runtimes = append(runtimes, runtime)
+		}
+		if preflight.ContainerRuntimeVersion == "" || compareVersions(version, preflight.ContainerRuntimeVersion) < 0 {
+			preflight.ContainerRuntimeVersion = version
+		}
+
+		if criu := node.Annotations[CRIUVersionAnnotation]; criu != "" {
+			if preflight.CRIUVersion == "" || compareVersions(criu, preflight.CRIUVersion) < 0 {
+				preflight.CRIUVersion = criu
+			}
+		}
+
+		if enabled, inspected := checkpointEnabled[node.Name]; !inspected {
+			preflight.UnreachableNodes = append(preflight.UnreachableNodes, node.Name)
+		} else if !enabled {
+			preflight.CheckpointEnabled = false
+		}
+	}
+	// The feature gate must be known to be enabled on at least one node
+	if len(preflight.UnreachableNodes) == len(nodes) {
+		preflight.CheckpointEnabled = false
+	}
+	slices.Sort(preflight.Architectures)
+	slices.Sort(runtimes)
+	preflight.ContainerRuntime = strings.Join(runtimes, ",")
+
+	return preflight
+}
+
+// clusterProblems returns why checkpoints cannot be taken on or restored to the cluster
+func clusterProblems(preflight *migrationv1.ClusterPreflight) []string {
+	if len(preflight.Architectures) == 0 {
+		return []string{"no ready nodes"}
+	}
+
+	var problems []string
+	if !preflight.CheckpointEnabled {
+		problems = append(problems, fmt.Sprintf("kubelet feature gate %s is not enabled on every node", containerCheckpointFeatureGate))
+	}
+	minimum, supported := minimumRuntimeVersions[preflight.ContainerRuntime]
+	if !supported {
+		problems = append(problems, fmt.Sprintf("container runtime %s does not support checkpoint and restore", preflight.ContainerRuntime))
+	} else if compareVersions(preflight.ContainerRuntimeVersion, minimum) < 0 {
+		problems = append(problems, fmt.Sprintf("container runtime %s %s is older than %s", preflight.ContainerRuntime, preflight.ContainerRuntimeVersion, minimum))
+	}
+	return problems
+}
+
+// migrationProblems returns why checkpoints taken on the source cluster cannot be restored on the target cluster
+func migrationProblems(source, target *migrationv1.ClusterPreflight) []string {
+	var problems []string
+	for _, architecture := range source.Architectures {
+		if !slices.Contains(target.Architectures, architecture) {
+			problems = append(problems, fmt.Sprintf("no %s nodes to restore checkpoints from cluster %s", architecture, source.Name))
+		}
+	}
+	if source.ContainerRuntime != target.ContainerRuntime {
+		problems = append(problems, fmt.Sprintf("container runtime %s cannot restore checkpoints of %s from cluster %s",
+			target.ContainerRuntime, source.ContainerRuntime, source.Name))
+	}
+	if source.CRIUVersion != "" && target.CRIUVersion != "" && compareVersions(target.CRIUVersion, source.CRIUVersion) < 0 {
+		problems = append(problems, fmt.Sprintf("CRIU %s is older than CRIU %s of cluster %s", target.CRIUVersion, source.CRIUVersion, source.Name))
+	}
+	if compareVersions(target.KernelVersion, source.KernelVersion) < 0 {
+		problems = append(problems, fmt.Sprintf("kernel %s is older than kernel %s of cluster %s", target.KernelVersion, source.KernelVersion, source.Name))
+	}
+	return problems
+}
+
+// preflightCondition evaluates the pre-flight results of the StatefulMigration's clusters into its Compatible condition
+func preflightCondition(statefulMigration *migrationv1.StatefulMigration) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               migrationv1.StatefulMigrationConditionCompatible,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: statefulMigration.Generation,
+		Reason:             ReasonPreflightPassed,
+	}
+
+	results := make(map[string]*migrationv1.ClusterPreflight)
+	for i := range statefulMigration.Status.Preflight {
+		results[statefulMigration.Status.Preflight[i].Name] = &statefulMigration.Status.Preflight[i]
+	}
+
+	var problems []string
+	for _, preflight := range statefulMigration.Status.Preflight {
+		if !preflight.Compatible {
+			problems = append(problems, fmt.Sprintf("cluster %s: %s", preflight.Name, preflight.Message))
+		}
+	}
+
+	// Checkpoints of every source cluster must be restorable on the target cluster
+	if target := results[statefulMigration.Spec.TargetCluster]; target != nil && target.Compatible {
+		for _, cluster := range sourceClusters(statefulMigration) {
+			source := results[cluster]
+			if source == nil || !source.Compatible || cluster == target.Name {
+				continue
+			}
+			for _, problem := range migrationProblems(source, target) {
+				problems = append(problems, fmt.Sprintf("target cluster %s: %s", target.Name, problem))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonPreflightFailed
+		condition.Message = strings.Join(problems, "; ")
+		return condition
+	}
+	condition.Message = fmt.Sprintf("%d cluster(s) passed the pre-flight checks", len(statefulMigration.Status.Preflight))
+	return condition
+}
+
+// kubeletFeatureEnabled reports whether the kubelet metrics show the feature gate as enabled
+func kubeletFeatureEnabled(metrics []byte, feature string) bool {
+	prefix := "kubernetes_feature_enabled{"
+	name := fmt.Sprintf("name=%q", feature)
+
+	scanner := bufio.NewScanner(bytes.NewReader(metrics))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		labels, value, found := strings.Cut(line[len(prefix):], "} ")
+		if !found || !slices.Contains(strings.Split(labels, ","), name) {
+			continue
+		}
+		return strings.TrimSpace(value) == "1"
+	}
+	return false
+}
+
+// compareVersions compares the leading dotted numbers of two versions, such as 1.28.1 or 5.15.0-91-generic.
+// It returns -1, 0 or 1 if a is older than, equal to or newer than b.
+func compareVersions(a, b string) int {
+	numbersA, numbersB := versionNumbers(a), versionNumbers(b)
+	for i := 0; i < max(len(numbersA), len(numbersB)); i++ {
+		var numberA, numberB int
+		if i < len(numbersA) {
+			numberA = numbersA[i]
+		}
+		if i < len(numbersB) {
+			numberB = numbersB[i]
+		}
+		if numberA != numberB {
+			if numberA < numberB {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionNumbers returns the leading dotted numbers of a version
+func versionNumbers(version string) []int {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+~ "); i >= 0 {
+		version = version[:i]
+	}
+
+	var numbers []int
+	for _, part := range strings.Split(version, ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
+// isNodeReady reports whether the node's Ready condition is true
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	restfake "k8s.io/client-go/rest/fake"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+var _ = Describe("Pre-flight checks", func() {
+	newNode := func(name, architecture, kernel, runtime, criu string) corev1.Node {
+		node := corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				NodeInfo: corev1.NodeSystemInfo{
+					Architecture:            architecture,
+					KernelVersion:           kernel,
+					ContainerRuntimeVersion: runtime,
+				},
+			},
+		}
+		if criu != "" {
+			node.Annotations = map[string]string{CRIUVersionAnnotation: criu}
+		}
+		return node
+	}
+
+	newMemberClusterClient := func(memberProxy func(*http.Request) (*http.Response, error)) *MemberClusterClient {
+		return &MemberClusterClient{karmadaClient: &KarmadaClient{restClient: &restfake.RESTClient{
+			NegotiatedSerializer: clientgoscheme.Codecs.WithoutConversion(),
+			Client:               restfake.CreateHTTPClient(memberProxy),
+		}}}
+	}
+
+	Context("When inspecting a cluster", func() {
+		It("should report the oldest versions of the nodes", func() {
+			nodes := []corev1.Node{
+				newNode("node-1", "amd64", "6.1.0-13-amd64", "cri-o://1.30.2", "3.19"),
+				newNode("node-2", "arm64", "5.15.0-91-generic", "cri-o://1.29.1", "3.17.1"),
+			}
+
+			preflight := summarizeNodes("member1", nodes, map[string]bool{"node-1": true, "node-2": true})
+			Expect(preflight.Architectures).To(Equal([]string{"amd64", "arm64"}))
+			Expect(preflight.KernelVersion).To(Equal("5.15.0-91-generic"))
+			Expect(preflight.ContainerRuntime).To(Equal("cri-o"))
+			Expect(preflight.ContainerRuntimeVersion).To(Equal("1.29.1"))
+			Expect(preflight.CRIUVersion).To(Equal("3.17.1"))
+			Expect(preflight.CheckpointEnabled).To(BeTrue())
+			Expect(clusterProblems(preflight)).To(BeEmpty())
+		})
+
+		It("should report clusters that cannot checkpoint", func() {
+			nodes := []corev1.Node{
+				newNode("node-1", "amd64", "6.1.0", "docker://24.0.7", ""),
+			}
+
+			preflight := summarizeNodes("member1", nodes, map[string]bool{"node-1": false})
+			Expect(preflight.CheckpointEnabled).To(BeFalse())
+			Expect(clusterProblems(preflight)).To(HaveLen(2))
+
+			Expect(clusterProblems(summarizeNodes("member2", nil, nil))).To(Equal([]string{"no ready nodes"}))
+		})
+
+		It("should report nodes whose kubelet cannot be reached instead of failing", func() {
+			ready := corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				NodeInfo: corev1.NodeSystemInfo{
+					Architecture:            "amd64",
+					KernelVersion:           "6.1.0",
+					ContainerRuntimeVersion: "cri-o://1.30.2",
+				},
+			}
+			nodeList := &corev1.NodeList{Items: []corev1.Node{
+				{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}, Status: ready},
+				{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}, Status: ready},
+			}}
+			metrics := `kubernetes_feature_enabled{name="ContainerCheckpoint",stage="BETA"} 1` + "\n"
+
+			memberProxy := func(req *http.Request) (*http.Response, error) {
+				header := http.Header{}
+				switch {
+				case strings.HasSuffix(req.URL.Path, "/proxy/api/v1/nodes"):
+					body, err := json.Marshal(nodeList)
+					if err != nil {
+						return nil, err
+					}
+					header.Set("Content-Type", "application/json")
+					return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(bytes.NewReader(body))}, nil
+				case strings.HasSuffix(req.URL.Path, "/nodes/node-1/proxy/metrics"):
+					header.Set("Content-Type", "text/plain")
+					return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader(metrics))}, nil
+				}
+				header.Set("Content-Type", "text/plain")
+				return &http.Response{StatusCode: http.StatusBadGateway, Header: header, Body: io.NopCloser(strings.NewReader("connection refused"))}, nil
+			}
+			memberClient := newMemberClusterClient(memberProxy)
+
+			preflight, err := inspectCluster(context.Background(), memberClient, "member1", time.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(preflight.UnreachableNodes).To(Equal([]string{"node-2"}))
+			Expect(preflight.CheckpointEnabled).To(BeTrue())
+			Expect(clusterProblems(preflight)).To(BeEmpty())
+
+			By("failing the checks when no kubelet can be reached")
+			nodeList.Items = nodeList.Items[1:]
+			preflight, err = inspectCluster(context.Background(), memberClient, "member1", time.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(preflight.CheckpointEnabled).To(BeFalse())
+			Expect(clusterProblems(preflight)).NotTo(BeEmpty())
+		})
+
+		It("should read the feature gate from the kubelet metrics", func() {
+			metrics := []byte(`# HELP kubernetes_feature_enabled [BETA] This metric records the data about the stage and enablement of a k8s feature.
+# TYPE kubernetes_feature_enabled gauge
+kubernetes_feature_enabled{name="CPUManager",stage=""} 1
+kubernetes_feature_enabled{name="ContainerCheckpoint",stage="BETA"} 1
+kubernetes_feature_enabled{name="InPlacePodVerticalScaling",stage="ALPHA"} 0
+`)
+			Expect(kubeletFeatureEnabled(metrics, "ContainerCheckpoint")).To(BeTrue())
+			Expect(kubeletFeatureEnabled(metrics, "InPlacePodVerticalScaling")).To(BeFalse())
+			Expect(kubeletFeatureEnabled(metrics, "Missing")).To(BeFalse())
+		})
+
+		It("should compare versions", func() {
+			Expect(compareVersions("1.28.1", "1.28.0")).To(Equal(1))
+			Expect(compareVersions("1.9", "1.10")).To(Equal(-1))
+			Expect(compareVersions("v3.17", "3.17.0")).To(Equal(0))
+			Expect(compareVersions("5.15.0-91-generic", "6.1.0")).To(Equal(-1))
+		})
+	})
+
+	Context("When comparing a source and a target cluster", func() {
+		source := &migrationv1.ClusterPreflight{
+			Name:                    "member1",
+			Architectures:           []string{"amd64"},
+			KernelVersion:           "6.1.0",
+			ContainerRuntime:        "cri-o",
+			ContainerRuntimeVersion: "1.30.2",
+			CRIUVersion:             "3.19",
+			CheckpointEnabled:       true,
+			Compatible:              true,
+		}
+
+		It("should accept a target with the same capabilities", func() {
+			Expect(migrationProblems(source, source.DeepCopy())).To(BeEmpty())
+		})
+
+		It("should reject a target with another architecture or an older CRIU", func() {
+			target := source.DeepCopy()
+			target.Name = "member2"
+			target.Architectures = []string{"arm64"}
+			target.CRIUVersion = "3.17"
+
+			Expect(migrationProblems(source, target)).To(HaveLen(2))
+		})
+
+		It("should surface incompatibilities in the Compatible condition", func() {
+			target := source.DeepCopy()
+			target.Name = "member2"
+			target.Architectures = []string{"arm64"}
+
+			statefulMigration := &migrationv1.StatefulMigration{
+				Spec: migrationv1.StatefulMigrationSpec{
+					SourceClusters: []string{"member1"},
+					TargetCluster:  "member2",
+				},
+				Status: migrationv1.StatefulMigrationStatus{
+					Preflight: []migrationv1.ClusterPreflight{*source, *target},
+				},
+			}
+
+			condition := preflightCondition(statefulMigration)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(ReasonPreflightFailed))
+			Expect(condition.Message).To(ContainSubstring("no amd64 nodes"))
+
+			statefulMigration.Spec.TargetCluster = ""
+			statefulMigration.Status.Preflight = []migrationv1.ClusterPreflight{*source}
+			Expect(preflightCondition(statefulMigration).Status).To(Equal(metav1.ConditionTrue))
+		})
+	})
+
+	Context("When several StatefulMigrations use the same clusters", func() {
+		It("should inspect each cluster once per interval", func() {
+			inspections := make(map[string]int)
+			memberProxy := func(req *http.Request) (*http.Response, error) {
+				cluster, _, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/apis/cluster.karmada.io/v1alpha1/clusters/"), "/")
+				inspections[cluster]++
+				body, err := json.Marshal(&corev1.NodeList{})
+				if err != nil {
+					return nil, err
+				}
+				header := http.Header{}
+				header.Set("Content-Type", "application/json")
+				return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(bytes.NewReader(body))}, nil
+			}
+			reconciler := &MigrationBackupReconciler{MemberClusterClient: newMemberClusterClient(memberProxy)}
+
+			newStatefulMigration := func(name, target string) *migrationv1.StatefulMigration {
+				return &migrationv1.StatefulMigration{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Spec: migrationv1.StatefulMigrationSpec{
+						SourceClusters: []string{"member1"},
+						TargetCluster:  target,
+					},
+				}
+			}
+
+			first := newStatefulMigration("web", "member2")
+			_, err := reconciler.reconcilePreflight(context.Background(), first)
+			Expect(err).NotTo(HaveOccurred())
+			second := newStatefulMigration("db", "")
+			_, err = reconciler.reconcilePreflight(context.Background(), second)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inspections).To(Equal(map[string]int{"member1": 1, "member2": 1}))
+			Expect(second.Status.Preflight).To(Equal(first.Status.Preflight[:1]))
+			Expect(second.Status.Preflight[0].Message).To(Equal("no ready nodes"))
+		})
+	})
+})
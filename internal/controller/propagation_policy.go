@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// CreatedByLabel marks the resources the operator creates on Karmada
+	CreatedByLabel = "created-by"
+	// CreatedByValue is the value of CreatedByLabel on the resources the operator creates
+	CreatedByValue = "stateful-migration-operator"
+	// ResourceTypeLabel tells what a PropagationPolicy created by the operator propagates
+	ResourceTypeLabel = "resource-type"
+
+	resourceTypeNamespace         = "namespace"
+	resourceTypeCheckpointBackup  = "checkpointbackup"
+	resourceTypeCheckpointRestore = "checkpointrestore"
+)
+
+// propagationPolicyName returns the name of the PropagationPolicy of a CheckpointBackup or CheckpointRestore
+func propagationPolicyName(name string) string {
+	return fmt.Sprintf("%s-policy", name)
+}
+
+// checkpointPropagationPolicy returns the PropagationPolicy that propagates a CheckpointBackup or CheckpointRestore,
+// and the extra resources it needs, to its cluster. The policy is controlled by the object, so that it is garbage
+// collected with it.
+func checkpointPropagationPolicy(owner client.Object, scheme *runtime.Scheme, resourceType, cluster string, extraSelectors ...karmadav1alpha1.ResourceSelector) (*karmadav1alpha1.PropagationPolicy, error) {
+	gvk, err := apiutil.GVKForObject(owner, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &karmadav1alpha1.PropagationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      propagationPolicyName(owner.GetName()),
+			Namespace: owner.GetNamespace(),
+			Labels: map[string]string{
+				CreatedByLabel:              CreatedByValue,
+				"app.kubernetes.io/part-of": "stateful-migration-operator",
+				ResourceTypeLabel:           resourceType,
+			},
+		},
+		Spec: karmadav1alpha1.PropagationSpec{
+			ResourceSelectors: append([]karmadav1alpha1.ResourceSelector{
+				{
+					APIVersion: gvk.GroupVersion().String(),
+					Kind:       gvk.Kind,
+					Name:       owner.GetName(),
+				},
+			}, extraSelectors...),
+			Placement: karmadav1alpha1.Placement{
+				ClusterAffinity: &karmadav1alpha1.ClusterAffinity{
+					ClusterNames: []string{cluster},
+				},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(owner, policy, scheme); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// deleteCheckpointPropagationPolicy deletes the PropagationPolicy of a CheckpointBackup or CheckpointRestore
+// without waiting for the garbage collector
+func deleteCheckpointPropagationPolicy(ctx context.Context, karmadaClient *KarmadaClient, owner client.Object) error {
+	if karmadaClient == nil {
+		return nil
+	}
+	policy := &karmadav1alpha1.PropagationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: propagationPolicyName(owner.GetName()), Namespace: owner.GetNamespace()},
+	}
+	if err := karmadaClient.DeletePropagationPolicy(ctx, policy); err != nil {
+		return fmt.Errorf("failed to delete PropagationPolicy %s: %w", policy.Name, err)
+	}
+	return nil
+}
+
+// propagationPolicyCollector deletes the PropagationPolicies created by the operator whose CheckpointBackup or
+// CheckpointRestore is gone, such as the policies of objects deleted while the operator was down.
+// It runs once, as soon as the operator is connected to Karmada.
+type propagationPolicyCollector struct {
+	connection *KarmadaConnection
+	// reader reads the owners of the policies, uncached so that recently deleted owners are not seen
+	reader client.Reader
+}
+
+// Start implements manager.Runnable. It waits for the Karmada connection and collects the policies, retrying
+// until a collection succeeds.
+func (c *propagationPolicyCollector) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("propagation-policy-collector")
+
+	ticker := time.NewTicker(karmadaCredentialsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if karmadaClient, _ := c.connection.Clients(); karmadaClient != nil {
+			err := c.collect(ctx, karmadaClient)
+			if err == nil {
+				return nil
+			}
+			log.Error(err, "Failed to collect orphaned PropagationPolicies")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect deletes the orphaned PropagationPolicies created by the operator. The policies of the migration
+// namespaces are shared by the StatefulMigrations and left to them.
+func (c *propagationPolicyCollector) collect(ctx context.Context, karmadaClient *KarmadaClient) error {
+	log := logf.FromContext(ctx).WithName("propagation-policy-collector")
+
+	var policies karmadav1alpha1.PropagationPolicyList
+	if err := karmadaClient.List(ctx, &policies, client.MatchingLabels{CreatedByLabel: CreatedByValue}); err != nil {
+		return fmt.Errorf("failed to list PropagationPolicies: %w", err)
+	}
+
+	deleted := 0
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if policy.Labels[ResourceTypeLabel] == resourceTypeNamespace || policy.DeletionTimestamp != nil {
+			continue
+		}
+		orphaned, err := policyOrphaned(ctx, c.reader, policy)
+		if err != nil {
+			return err
+		}
+		if !orphaned {
+			continue
+		}
+		if err := karmadaClient.DeletePropagationPolicy(ctx, policy); err != nil {
+			return fmt.Errorf("failed to delete PropagationPolicy %s/%s: %w", policy.Namespace, policy.Name, err)
+		}
+		deleted++
+	}
+
+	log.Info("Collected orphaned PropagationPolicies", "policies", len(policies.Items), "deleted", deleted)
+	return nil
+}
+
+// policyOrphaned reports whether the PropagationPolicy has no owner, or its owner no longer exists
+func policyOrphaned(ctx context.Context, reader client.Reader, policy *karmadav1alpha1.PropagationPolicy) (bool, error) {
+	ownerRef := metav1.GetControllerOf(policy)
+	if ownerRef == nil {
+		return true, nil
+	}
+
+	owner := &metav1.PartialObjectMetadata{}
+	owner.APIVersion = ownerRef.APIVersion
+	owner.Kind = ownerRef.Kind
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: policy.Namespace, Name: ownerRef.Name}, owner); err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to get owner %s %s of PropagationPolicy %s/%s: %w", ownerRef.Kind, ownerRef.Name, policy.Namespace, policy.Name, err)
+	}
+	// An owner recreated with the same name does not own the policy
+	return owner.UID != ownerRef.UID, nil
+}
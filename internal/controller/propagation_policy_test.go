@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	karmadav1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+var _ = Describe("PropagationPolicies", func() {
+	ctx := context.Background()
+
+	newBackup := func(name string) *migrationv1.CheckpointBackup {
+		return &migrationv1.CheckpointBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: migrationv1.CheckpointBackupSpec{
+				Schedule: "*/5 * * * *",
+				PodRef:   migrationv1.PodRef{Namespace: "default", Name: "web-0"},
+				ResourceRef: migrationv1.ResourceRef{
+					APIVersion: "apps/v1",
+					Kind:       "StatefulSet",
+					Namespace:  "default",
+					Name:       "web",
+				},
+				Registry: migrationv1.Registry{URL: "registry.example.com", Repository: "checkpoints"},
+			},
+		}
+	}
+
+	Context("When propagating a CheckpointBackup", func() {
+		It("should label the policy and make the backup its owner", func() {
+			backup := newBackup("owned-web-0-member1")
+			backup.UID = types.UID("backup-uid")
+
+			policy, err := checkpointPropagationPolicy(backup, k8sClient.Scheme(), resourceTypeCheckpointBackup, "member1",
+				karmadav1alpha1.ResourceSelector{APIVersion: "v1", Kind: "Secret", Name: "registry"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.Name).To(Equal("owned-web-0-member1-policy"))
+			Expect(policy.Labels).To(HaveKeyWithValue(CreatedByLabel, CreatedByValue))
+			Expect(policy.Labels).To(HaveKeyWithValue(ResourceTypeLabel, resourceTypeCheckpointBackup))
+			Expect(policy.Spec.ResourceSelectors).To(Equal([]karmadav1alpha1.ResourceSelector{
+				{APIVersion: "migration.dcnlab.com/v1", Kind: "CheckpointBackup", Name: backup.Name},
+				{APIVersion: "v1", Kind: "Secret", Name: "registry"},
+			}))
+			Expect(policy.Spec.Placement.ClusterAffinity.ClusterNames).To(Equal([]string{"member1"}))
+
+			owner := metav1.GetControllerOf(policy)
+			Expect(owner).NotTo(BeNil())
+			Expect(owner.Kind).To(Equal("CheckpointBackup"))
+			Expect(owner.UID).To(Equal(backup.UID))
+		})
+	})
+
+	Context("When collecting orphaned policies", func() {
+		It("should only collect policies whose owner is gone", func() {
+			backup := newBackup("collect-web-0-member1")
+			Expect(k8sClient.Create(ctx, backup)).To(Succeed())
+			DeferCleanup(func() {
+				_ = k8sClient.Delete(ctx, backup)
+			})
+
+			policy, err := checkpointPropagationPolicy(backup, k8sClient.Scheme(), resourceTypeCheckpointBackup, "member1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policyOrphaned(ctx, k8sClient, policy)).To(BeFalse())
+
+			// A backup recreated with the same name does not own the policy of the previous one
+			recreated := policy.DeepCopy()
+			recreated.OwnerReferences[0].UID = types.UID("previous-uid")
+			Expect(policyOrphaned(ctx, k8sClient, recreated)).To(BeTrue())
+
+			unowned := policy.DeepCopy()
+			unowned.OwnerReferences = nil
+			Expect(policyOrphaned(ctx, k8sClient, unowned)).To(BeTrue())
+
+			Expect(k8sClient.Delete(ctx, backup)).To(Succeed())
+			Expect(policyOrphaned(ctx, k8sClient, policy)).To(BeTrue())
+		})
+	})
+})
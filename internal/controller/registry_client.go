@@ -0,0 +1,574 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// CheckpointNameAnnotation is the annotation CRI-O and containerd use to recognise a checkpoint image
+	// and to find the name of the container it was taken from
+	CheckpointNameAnnotation = "io.kubernetes.cri-o.annotations.checkpoint.name"
+
+	// OCI media types used for checkpoint images
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	ociConfigMediaType   = "application/vnd.oci.image.config.v1+json"
+	ociLayerMediaType    = "application/vnd.oci.image.layer.v1.tar"
+
+	// dockerHubRegistry is the registry host docker.io references resolve to
+	dockerHubRegistry = "registry-1.docker.io"
+)
+
+// RegistryCredentials holds the credentials used to authenticate against a registry
+type RegistryCredentials struct {
+	Username string
+	Password string
+}
+
+// CheckpointImage describes a checkpoint archive to be pushed as a single-layer OCI image
+type CheckpointImage struct {
+	// Reference is the image reference to push to, in the form registry/repository:tag
+	Reference string
+	// ContainerName is the name of the checkpointed container
+	ContainerName string
+	// ArchivePath is the path of the checkpoint archive on the local filesystem
+	ArchivePath string
+	// Architecture and OS of the node the checkpoint was taken on
+	Architecture string
+	OS           string
+	// Created is the time the checkpoint was taken
+	Created time.Time
+}
+
+// PushedImage describes an image pushed to a registry
+type PushedImage struct {
+	// Reference is the image reference that was pushed
+	Reference string
+	// Digest is the digest of the image manifest
+	Digest string
+	// Size is the size in bytes of the checkpoint layer
+	Size int64
+}
+
+// RegistryClient pushes checkpoint images to registries implementing the OCI distribution API
+type RegistryClient struct {
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// imageReference is a parsed image reference
+type imageReference struct {
+	scheme     string
+	host       string
+	repository string
+	tag        string
+}
+
+// ociDescriptor is an OCI content descriptor
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociManifest is an OCI image manifest
+type ociManifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	Config        ociDescriptor     `json:"config"`
+	Layers        []ociDescriptor   `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// ociImageConfig is an OCI image configuration
+type ociImageConfig struct {
+	Created      string            `json:"created"`
+	Architecture string            `json:"architecture"`
+	OS           string            `json:"os"`
+	Config       map[string]any    `json:"config"`
+	RootFS       ociRootFS         `json:"rootfs"`
+	History      []ociHistory      `json:"history,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+}
+
+// ociRootFS lists the layers of an OCI image configuration
+type ociRootFS struct {
+	Type    string   `json:"type"`
+	DiffIDs []string `json:"diff_ids"`
+}
+
+// ociHistory describes how a layer of an OCI image was created
+type ociHistory struct {
+	Created   string `json:"created"`
+	CreatedBy string `json:"created_by"`
+}
+
+// dockerConfigJSON is the content of a kubernetes.io/dockerconfigjson Secret
+type dockerConfigJSON struct {
+	Auths map[string]struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Auth     string `json:"auth"`
+	} `json:"auths"`
+}
+
+// NewRegistryClient creates a new registry client
+func NewRegistryClient() *RegistryClient {
+	return &RegistryClient{
+		httpClient: &http.Client{},
+		tokens:     make(map[string]string),
+	}
+}
+
+// ImageReference builds the reference of an image stored under registryURL/repository with the given tag
+func ImageReference(registryURL, repository, tag string) string {
+	host := strings.TrimSuffix(registryURL, "/")
+	return fmt.Sprintf("%s/%s:%s", host, strings.Trim(repository, "/"), tag)
+}
+
+// CredentialsFromSecret extracts the registry credentials for registryURL from a Secret.
+// Both kubernetes.io/dockerconfigjson Secrets and Secrets with username and password keys are supported.
+func CredentialsFromSecret(secret *corev1.Secret, registryURL string) (*RegistryCredentials, error) {
+	if data, ok := secret.Data[corev1.DockerConfigJsonKey]; ok {
+		var config dockerConfigJSON
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse %s in secret %s: %w", corev1.DockerConfigJsonKey, secret.Name, err)
+		}
+
+		ref, err := parseImageReference(ImageReference(registryURL, "credentials", "latest"))
+		if err != nil {
+			return nil, err
+		}
+
+		for server, auth := range config.Auths {
+			if !registryHostMatches(server, ref.host) {
+				continue
+			}
+			if auth.Username != "" || auth.Password != "" {
+				return &RegistryCredentials{Username: auth.Username, Password: auth.Password}, nil
+			}
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode auth for %s in secret %s: %w", server, secret.Name, err)
+			}
+			username, password, _ := strings.Cut(string(decoded), ":")
+			return &RegistryCredentials{Username: username, Password: password}, nil
+		}
+
+		return nil, fmt.Errorf("secret %s has no credentials for registry %s", secret.Name, registryURL)
+	}
+
+	username, hasUsername := secret.Data["username"]
+	password, hasPassword := secret.Data["password"]
+	if !hasUsername || !hasPassword {
+		return nil, fmt.Errorf("secret %s must contain either %s or username and password keys", secret.Name, corev1.DockerConfigJsonKey)
+	}
+
+	return &RegistryCredentials{Username: string(username), Password: string(password)}, nil
+}
+
+// PushCheckpointImage packages a checkpoint archive as a single-layer OCI image and pushes it to the registry
+func (c *RegistryClient) PushCheckpointImage(ctx context.Context, image CheckpointImage, credentials *RegistryCredentials) (*PushedImage, error) {
+	logger := log.FromContext(ctx)
+
+	ref, err := parseImageReference(image.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	// The checkpoint archive written by the kubelet is an uncompressed tar, so it is used as the layer as-is
+	layerDigest, layerSize, err := digestFile(image.ArchivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint archive %s: %w", image.ArchivePath, err)
+	}
+
+	created := image.Created.UTC().Format(time.RFC3339)
+	configBlob, err := json.Marshal(ociImageConfig{
+		Created:      created,
+		Architecture: image.Architecture,
+		OS:           image.OS,
+		Config:       map[string]any{},
+		RootFS: ociRootFS{
+			Type:    "layers",
+			DiffIDs: []string{layerDigest},
+		},
+		History: []ociHistory{{
+			Created:   created,
+			CreatedBy: "stateful-migration-operator checkpoint of container " + image.ContainerName,
+		}},
+		Labels: map[string]string{
+			CheckpointNameAnnotation: image.ContainerName,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal image config: %w", err)
+	}
+	configDigest := digestBytes(configBlob)
+
+	manifestBlob, err := json.Marshal(ociManifest{
+		SchemaVersion: 2,
+		MediaType:     ociManifestMediaType,
+		Config: ociDescriptor{
+			MediaType: ociConfigMediaType,
+			Digest:    configDigest,
+			Size:      int64(len(configBlob)),
+		},
+		Layers: []ociDescriptor{{
+			MediaType: ociLayerMediaType,
+			Digest:    layerDigest,
+			Size:      layerSize,
+		}},
+		Annotations: map[string]string{
+			CheckpointNameAnnotation:           image.ContainerName,
+			"org.opencontainers.image.created": created,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal image manifest: %w", err)
+	}
+
+	// Upload the layer and the config before the manifest that references them
+	if err := c.pushBlob(ctx, ref, layerDigest, layerSize, func() (io.ReadCloser, error) {
+		return os.Open(image.ArchivePath)
+	}, credentials); err != nil {
+		return nil, fmt.Errorf("failed to push checkpoint layer: %w", err)
+	}
+	if err := c.pushBlob(ctx, ref, configDigest, int64(len(configBlob)), func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(configBlob)), nil
+	}, credentials); err != nil {
+		return nil, fmt.Errorf("failed to push image config: %w", err)
+	}
+
+	manifestDigest := digestBytes(manifestBlob)
+	resp, err := c.do(ctx, ref, credentials, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, ref.url("/manifests/"+ref.tag), bytes.NewReader(manifestBlob))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", ociManifestMediaType)
+		return req, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to push image manifest: %w", err)
+	}
+	defer drain(resp)
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to push image manifest: %s", responseError(resp))
+	}
+	if digest := resp.Header.Get("Docker-Content-Digest"); digest != "" && digest != manifestDigest {
+		return nil, fmt.Errorf("registry reported manifest digest %s, expected %s", digest, manifestDigest)
+	}
+
+	logger.Info("Successfully pushed checkpoint image",
+		"image", image.Reference, "digest", manifestDigest, "size", layerSize)
+
+	return &PushedImage{
+		Reference: strings.TrimPrefix(strings.TrimPrefix(image.Reference, "https://"), "http://"),
+		Digest:    manifestDigest,
+		Size:      layerSize,
+	}, nil
+}
+
+// pushBlob uploads a blob unless the registry already has it
+func (c *RegistryClient) pushBlob(ctx context.Context, ref *imageReference, digest string, size int64, open func() (io.ReadCloser, error), credentials *RegistryCredentials) error {
+	resp, err := c.do(ctx, ref, credentials, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodHead, ref.url("/blobs/"+digest), nil)
+	})
+	if err != nil {
+		return err
+	}
+	drain(resp)
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	// Start an upload session
+	resp, err = c.do(ctx, ref, credentials, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodPost, ref.url("/blobs/uploads/"), nil)
+	})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		defer drain(resp)
+		return fmt.Errorf("failed to start blob upload: %s", responseError(resp))
+	}
+	drain(resp)
+
+	location, err := resp.Location()
+	if err != nil {
+		return fmt.Errorf("registry returned no upload location: %w", err)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	// Upload the blob in a single request
+	resp, err = c.do(ctx, ref, credentials, func() (*http.Request, error) {
+		body, err := open()
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, location.String(), body)
+		if err != nil {
+			_ = body.Close()
+			return nil, err
+		}
+		req.ContentLength = size
+		req.Header.Set("Content-Type", "application/octet-stream")
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	defer drain(resp)
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to upload blob %s: %s", digest, responseError(resp))
+	}
+
+	return nil
+}
+
+// do sends a request to the registry, answering Basic and Bearer authentication challenges
+func (c *RegistryClient) do(ctx context.Context, ref *imageReference, credentials *RegistryCredentials, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	req, err := newRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	token := c.tokens[ref.host+"/"+ref.repository]
+	c.mu.Unlock()
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+	drain(resp)
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	scheme, params := parseChallenge(challenge)
+
+	req, err = newRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if credentials == nil {
+			return nil, fmt.Errorf("registry %s requires credentials", ref.host)
+		}
+		req.SetBasicAuth(credentials.Username, credentials.Password)
+	case "bearer":
+		token, err := c.fetchToken(ctx, params, ref, credentials)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.tokens[ref.host+"/"+ref.repository] = token
+		c.mu.Unlock()
+		req.Header.Set("Authorization", "Bearer "+token)
+	default:
+		return nil, fmt.Errorf("unsupported authentication challenge %q from registry %s", challenge, ref.host)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// fetchToken requests a bearer token with push access to the repository from the registry's token service
+func (c *RegistryClient) fetchToken(ctx context.Context, params map[string]string, ref *imageReference, credentials *RegistryCredentials) (string, error) {
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("bearer challenge from registry %s has no realm", ref.host)
+	}
+
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("invalid token realm %q: %w", realm, err)
+	}
+	query := tokenURL.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull,push", ref.repository))
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if credentials != nil {
+		req.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch registry token: %w", err)
+	}
+	defer drain(resp)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch registry token: %s", responseError(resp))
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode registry token: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", fmt.Errorf("registry token service returned no token")
+}
+
+// url returns the distribution API URL for the given path under the repository
+func (r *imageReference) url(path string) string {
+	return fmt.Sprintf("%s://%s/v2/%s%s", r.scheme, r.host, r.repository, path)
+}
+
+// parseImageReference parses a [scheme://]host/repository:tag reference
+func parseImageReference(reference string) (*imageReference, error) {
+	ref := &imageReference{scheme: "https"}
+
+	rest := reference
+	if scheme, remainder, found := strings.Cut(rest, "://"); found {
+		if scheme != "http" && scheme != "https" {
+			return nil, fmt.Errorf("invalid image reference %q: unsupported scheme %s", reference, scheme)
+		}
+		ref.scheme = scheme
+		rest = remainder
+	}
+
+	host, path, found := strings.Cut(rest, "/")
+	if !found || host == "" || path == "" {
+		return nil, fmt.Errorf("invalid image reference %q: expected registry/repository:tag", reference)
+	}
+
+	repository, tag := path, "latest"
+	if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") {
+		repository, tag = path[:i], path[i+1:]
+	}
+	if repository == "" || tag == "" {
+		return nil, fmt.Errorf("invalid image reference %q: expected registry/repository:tag", reference)
+	}
+
+	if host == "docker.io" || host == "index.docker.io" {
+		host = dockerHubRegistry
+		if !strings.Contains(repository, "/") {
+			repository = "library/" + repository
+		}
+	}
+
+	ref.host = host
+	ref.repository = repository
+	ref.tag = tag
+	return ref, nil
+}
+
+// registryHostMatches reports whether a docker config server entry refers to the registry host
+func registryHostMatches(server, host string) bool {
+	server = strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	server, _, _ = strings.Cut(server, "/")
+	if server == host {
+		return true
+	}
+	dockerHub := map[string]bool{"docker.io": true, "index.docker.io": true, dockerHubRegistry: true}
+	return dockerHub[server] && dockerHub[host]
+}
+
+// parseChallenge parses a WWW-Authenticate header into its scheme and parameters
+func parseChallenge(header string) (string, map[string]string) {
+	params := make(map[string]string)
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	for _, part := range strings.Split(rest, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		params[strings.ToLower(key)] = strings.Trim(value, `"`)
+	}
+	return scheme, params
+}
+
+// digestFile returns the sha256 digest and size of a file
+func digestFile(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// digestBytes returns the sha256 digest of data
+func digestBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// responseError describes an unexpected registry response
+func responseError(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if len(body) == 0 {
+		return resp.Status
+	}
+	return fmt.Sprintf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+// drain reads and closes a response body so the connection can be reused
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	_ = resp.Body.Close()
+}
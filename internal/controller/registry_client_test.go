@@ -0,0 +1,245 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeRegistry is a minimal in-memory stand-in for a registry:2 server
+type fakeRegistry struct {
+	mu        sync.Mutex
+	username  string
+	password  string
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	uploads   int
+}
+
+func newFakeRegistry(username, password string) *fakeRegistry {
+	return &fakeRegistry{
+		username:  username,
+		password:  password,
+		blobs:     make(map[string][]byte),
+		manifests: make(map[string][]byte),
+	}
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if f.username != "" {
+		username, password, ok := req.BasicAuth()
+		if !ok || username != f.username || password != f.password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="fake-registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	switch {
+	case req.Method == http.MethodHead && strings.Contains(path, "/blobs/"):
+		digest := path[strings.LastIndex(path, "/")+1:]
+		if _, ok := f.blobs[digest]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+
+	case req.Method == http.MethodPost && strings.HasSuffix(path, "/blobs/uploads/"):
+		f.uploads++
+		w.Header().Set("Location", fmt.Sprintf("/v2/%s%d", path, f.uploads))
+		w.WriteHeader(http.StatusAccepted)
+
+	case req.Method == http.MethodPut && strings.Contains(path, "/blobs/uploads/"):
+		body, _ := io.ReadAll(req.Body)
+		digest := req.URL.Query().Get("digest")
+		if digestBytes(body) != digest {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.blobs[digest] = body
+		w.WriteHeader(http.StatusCreated)
+
+	case req.Method == http.MethodPut && strings.Contains(path, "/manifests/"):
+		body, _ := io.ReadAll(req.Body)
+		var manifest ociManifest
+		if err := json.Unmarshal(body, &manifest); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, descriptor := range append(manifest.Layers, manifest.Config) {
+			if _, ok := f.blobs[descriptor.Digest]; !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		f.manifests[path] = body
+		w.Header().Set("Docker-Content-Digest", digestBytes(body))
+		w.WriteHeader(http.StatusCreated)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// writeCheckpointArchive writes a small tar archive shaped like a kubelet checkpoint
+func writeCheckpointArchive(dir string) string {
+	path := filepath.Join(dir, "checkpoint-test-pod_default-app-2025-01-01T10:00:00Z.tar")
+	file, err := os.Create(path)
+	Expect(err).NotTo(HaveOccurred())
+	defer func() { _ = file.Close() }()
+
+	writer := tar.NewWriter(file)
+	for name, content := range map[string]string{
+		"config.dump": `{"id":"abc"}`,
+		"spec.dump":   `{"ociVersion":"1.0.0"}`,
+	} {
+		Expect(writer.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content))})).To(Succeed())
+		_, err := writer.Write([]byte(content))
+		Expect(err).NotTo(HaveOccurred())
+	}
+	Expect(writer.Close()).To(Succeed())
+	return path
+}
+
+var _ = Describe("Registry client", func() {
+	Context("When pushing a checkpoint image", func() {
+		var (
+			registry *fakeRegistry
+			server   *httptest.Server
+			archive  string
+		)
+
+		BeforeEach(func() {
+			registry = newFakeRegistry("user", "secret")
+			server = httptest.NewServer(registry)
+			archive = writeCheckpointArchive(GinkgoT().TempDir())
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should push a single-layer OCI image annotated as a checkpoint", func() {
+			created := time.Date(2025, time.January, 1, 10, 0, 0, 0, time.UTC)
+			reference := ImageReference(server.URL, "checkpoints", "test-pod-app-20250101100000")
+
+			pushed, err := NewRegistryClient().PushCheckpointImage(context.Background(), CheckpointImage{
+				Reference:     reference,
+				ContainerName: "app",
+				ArchivePath:   archive,
+				Architecture:  "amd64",
+				OS:            "linux",
+				Created:       created,
+			}, &RegistryCredentials{Username: "user", Password: "secret"})
+			Expect(err).NotTo(HaveOccurred())
+
+			info, err := os.Stat(archive)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pushed.Size).To(Equal(info.Size()))
+			Expect(pushed.Reference).To(Equal(strings.TrimPrefix(reference, "http://")))
+
+			By("Checking the pushed manifest")
+			manifestBlob, ok := registry.manifests["checkpoints/manifests/test-pod-app-20250101100000"]
+			Expect(ok).To(BeTrue())
+			Expect(pushed.Digest).To(Equal(digestBytes(manifestBlob)))
+
+			var manifest ociManifest
+			Expect(json.Unmarshal(manifestBlob, &manifest)).To(Succeed())
+			Expect(manifest.MediaType).To(Equal(ociManifestMediaType))
+			Expect(manifest.Annotations).To(HaveKeyWithValue(CheckpointNameAnnotation, "app"))
+			Expect(manifest.Layers).To(HaveLen(1))
+			Expect(manifest.Layers[0].MediaType).To(Equal(ociLayerMediaType))
+
+			archiveDigest, _, err := digestFile(archive)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest.Layers[0].Digest).To(Equal(archiveDigest))
+
+			By("Checking the pushed image config")
+			var config ociImageConfig
+			Expect(json.Unmarshal(registry.blobs[manifest.Config.Digest], &config)).To(Succeed())
+			Expect(config.Architecture).To(Equal("amd64"))
+			Expect(config.RootFS.DiffIDs).To(Equal([]string{archiveDigest}))
+		})
+
+		It("should fail without valid credentials", func() {
+			_, err := NewRegistryClient().PushCheckpointImage(context.Background(), CheckpointImage{
+				Reference:     ImageReference(server.URL, "checkpoints", "latest"),
+				ContainerName: "app",
+				ArchivePath:   archive,
+				Created:       time.Now(),
+			}, &RegistryCredentials{Username: "user", Password: "wrong"})
+			Expect(err).To(HaveOccurred())
+			Expect(registry.manifests).To(BeEmpty())
+		})
+	})
+
+	Context("When reading registry credentials", func() {
+		It("should read username and password keys", func() {
+			credentials, err := CredentialsFromSecret(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "registry-secret"},
+				Data: map[string][]byte{
+					"username": []byte("user"),
+					"password": []byte("secret"),
+				},
+			}, "docker.io")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*credentials).To(Equal(RegistryCredentials{Username: "user", Password: "secret"}))
+		})
+
+		It("should read the matching entry of a docker config secret", func() {
+			credentials, err := CredentialsFromSecret(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "registry-secret"},
+				Type:       corev1.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{
+					corev1.DockerConfigJsonKey: []byte(`{"auths":{
+						"other.example.com":{"username":"other","password":"other"},
+						"https://index.docker.io/v1/":{"auth":"dXNlcjpzZWNyZXQ="}}}`),
+				},
+			}, "docker.io")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*credentials).To(Equal(RegistryCredentials{Username: "user", Password: "secret"}))
+		})
+
+		It("should reject secrets without credentials", func() {
+			_, err := CredentialsFromSecret(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "registry-secret"},
+				Data:       map[string][]byte{"token": []byte("abc")},
+			}, "docker.io")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
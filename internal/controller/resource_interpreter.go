@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	configv1alpha1 "github.com/karmada-io/karmada/pkg/apis/config/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// checkpointStatusReflection picks the status reported by the agent on the member cluster, along with the
+// generation of the member copy so that the aggregation can tell whether the agent saw the latest spec
+const checkpointStatusReflection = `function ReflectStatus(observedObj)
+  if observedObj.status == nil then
+    return {}
+  end
+  local status = observedObj.status
+  status.generation = observedObj.metadata.generation
+  return status
+end`
+
+// checkpointStatusAggregation copies the status reported on the member cluster to the object on Karmada. The object
+// is propagated to a single cluster, so the status of that cluster is the status of the object. The observed
+// generation of the member copy is translated to the generation of the object on Karmada.
+const checkpointStatusAggregation = `function AggregateStatus(desiredObj, statusItems)
+  if statusItems == nil then
+    return desiredObj
+  end
+  for i = 1, #statusItems do
+    local status = statusItems[i].status
+    if status ~= nil and status.generation ~= nil then
+      local previous = desiredObj.status or {}
+      if status.observedGeneration == status.generation then
+        status.observedGeneration = desiredObj.metadata.generation
+      else
+        status.observedGeneration = previous.observedGeneration
+      end
+      status.generation = nil
+      desiredObj.status = status
+    end
+  end
+  return desiredObj
+end`
+
+// checkpointHealthInterpretation reports the object healthy unless its last run failed
+const checkpointHealthInterpretation = `function InterpretHealth(observedObj)
+  if observedObj.status == nil or observedObj.status.phase == nil then
+    return true
+  end
+  return observedObj.status.phase ~= 'Failed'
+end`
+
+// resourceInterpreterCustomizations returns the ResourceInterpreterCustomizations that teach Karmada how to
+// collect the status of the CheckpointBackups and CheckpointRestores from the member clusters
+func resourceInterpreterCustomizations() []*configv1alpha1.ResourceInterpreterCustomization {
+	var customizations []*configv1alpha1.ResourceInterpreterCustomization
+	for _, kind := range []string{"CheckpointBackup", "CheckpointRestore"} {
+		customizations = append(customizations, &configv1alpha1.ResourceInterpreterCustomization{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("stateful-migration-%s", strings.ToLower(kind)),
+				Labels: map[string]string{
+					CreatedByLabel:              CreatedByValue,
+					"app.kubernetes.io/part-of": "stateful-migration-operator",
+				},
+			},
+			Spec: configv1alpha1.ResourceInterpreterCustomizationSpec{
+				Target: configv1alpha1.CustomizationTarget{
+					APIVersion: migrationv1.GroupVersion.String(),
+					Kind:       kind,
+				},
+				Customizations: configv1alpha1.CustomizationRules{
+					StatusReflection:     &configv1alpha1.StatusReflection{LuaScript: checkpointStatusReflection},
+					StatusAggregation:    &configv1alpha1.StatusAggregation{LuaScript: checkpointStatusAggregation},
+					HealthInterpretation: &configv1alpha1.HealthInterpretation{LuaScript: checkpointHealthInterpretation},
+				},
+			},
+		})
+	}
+	return customizations
+}
+
+// +kubebuilder:rbac:groups=config.karmada.io,resources=resourceinterpretercustomizations,verbs=get;create;update
+
+// resourceInterpreterInstaller installs the ResourceInterpreterCustomizations of the operator on Karmada and
+// restores them if they are changed or deleted
+type resourceInterpreterInstaller struct {
+	connection *KarmadaConnection
+}
+
+// Start implements manager.Runnable. It checks the customizations as often as the Karmada credentials, until the
+// manager stops.
+func (i *resourceInterpreterInstaller) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("resource-interpreter-installer")
+
+	ticker := time.NewTicker(karmadaCredentialsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if karmadaClient, _ := i.connection.Clients(); karmadaClient != nil {
+			for _, customization := range resourceInterpreterCustomizations() {
+				result, err := karmadaClient.ApplyResourceInterpreterCustomization(ctx, customization)
+				if err != nil {
+					log.Error(err, "Failed to install ResourceInterpreterCustomization", "name", customization.Name)
+					continue
+				}
+				if result != controllerutil.OperationResultNone {
+					log.Info("Installed ResourceInterpreterCustomization", "name", customization.Name, "operation", result)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	configv1alpha1 "github.com/karmada-io/karmada/pkg/apis/config/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("Resource interpreter customizations", func() {
+	ctx := context.Background()
+
+	Context("When describing the customizations", func() {
+		It("should interpret the status and health of both kinds", func() {
+			customizations := resourceInterpreterCustomizations()
+			Expect(customizations).To(HaveLen(2))
+
+			var kinds []string
+			for _, customization := range customizations {
+				kinds = append(kinds, customization.Spec.Target.Kind)
+				Expect(customization.Spec.Target.APIVersion).To(Equal("migration.dcnlab.com/v1"))
+				Expect(customization.Labels).To(HaveKeyWithValue(CreatedByLabel, CreatedByValue))
+
+				rules := customization.Spec.Customizations
+				Expect(rules.StatusReflection.LuaScript).To(HavePrefix("function ReflectStatus(observedObj)"))
+				Expect(rules.StatusAggregation.LuaScript).To(HavePrefix("function AggregateStatus(desiredObj, statusItems)"))
+				Expect(rules.HealthInterpretation.LuaScript).To(HavePrefix("function InterpretHealth(observedObj)"))
+			}
+			Expect(kinds).To(ConsistOf("CheckpointBackup", "CheckpointRestore"))
+			Expect(customizations[0].Name).To(Equal("stateful-migration-checkpointbackup"))
+		})
+	})
+
+	Context("When installing the customizations", func() {
+		It("should create them and restore them when they drift", func() {
+			scheme := runtime.NewScheme()
+			Expect(configv1alpha1.AddToScheme(scheme)).To(Succeed())
+			karmadaClient := &KarmadaClient{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+
+			customization := resourceInterpreterCustomizations()[0]
+			result, err := karmadaClient.ApplyResourceInterpreterCustomization(ctx, customization.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(controllerutil.OperationResultCreated))
+
+			result, err = karmadaClient.ApplyResourceInterpreterCustomization(ctx, customization.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(controllerutil.OperationResultNone))
+
+			installed := &configv1alpha1.ResourceInterpreterCustomization{}
+			Expect(karmadaClient.Get(ctx, client.ObjectKeyFromObject(customization), installed)).To(Succeed())
+			installed.Spec.Customizations.HealthInterpretation = nil
+			Expect(karmadaClient.Update(ctx, installed)).To(Succeed())
+
+			result, err = karmadaClient.ApplyResourceInterpreterCustomization(ctx, customization.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(controllerutil.OperationResultUpdated))
+			Expect(karmadaClient.Get(ctx, client.ObjectKeyFromObject(customization), installed)).To(Succeed())
+			Expect(installed.Spec).To(Equal(customization.Spec))
+		})
+	})
+})
@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// maxMissedSchedules is the number of missed schedules after which the controller stops counting
+	// and asks for the schedule or startingDeadlineSeconds to be fixed
+	maxMissedSchedules = 100
+)
+
+// parseSchedule parses a cron expression in standard five-field or descriptor (@hourly, @every 1h) syntax
+func parseSchedule(schedule string) (cron.Schedule, error) {
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("unparseable schedule %q: %w", schedule, err)
+	}
+	return sched, nil
+}
+
+// getNextSchedule returns the most recent schedule time that has been missed since earliestTime,
+// or the zero time if none has, together with the next schedule time after now.
+// If startingDeadlineSeconds is set, schedules older than the deadline are not considered missed.
+func getNextSchedule(sched cron.Schedule, earliestTime, now time.Time, startingDeadlineSeconds *int64) (lastMissed time.Time, next time.Time, err error) {
+	if startingDeadlineSeconds != nil {
+		// Controller is not going to schedule anything below this point
+		schedulingDeadline := now.Add(-time.Second * time.Duration(*startingDeadlineSeconds))
+		if schedulingDeadline.After(earliestTime) {
+			earliestTime = schedulingDeadline
+		}
+	}
+
+	if earliestTime.After(now) {
+		return time.Time{}, sched.Next(now), nil
+	}
+
+	missed := 0
+	for t := sched.Next(earliestTime); !t.After(now); t = sched.Next(t) {
+		lastMissed = t
+		missed++
+		// An object might miss several starts, for example when the controller is down.
+		// Counting them all would be expensive for frequent schedules, so bail out.
+		if missed > maxMissedSchedules {
+			return time.Time{}, time.Time{}, fmt.Errorf("too many missed start times (> %d), set or decrease startingDeadlineSeconds or check clock skew", maxMissedSchedules)
+		}
+	}
+
+	return lastMissed, sched.Next(now), nil
+}
+
+// checkpointRun is an in-flight checkpoint started for a schedule time
+type checkpointRun struct {
+	scheduledTime time.Time
+	cancel        context.CancelFunc
+}
+
+// checkpointRunTracker keeps track of the checkpoints that are currently running for each CheckpointBackup
+type checkpointRunTracker struct {
+	mu   sync.Mutex
+	runs map[types.NamespacedName][]*checkpointRun
+}
+
+// newCheckpointRunTracker creates an empty checkpointRunTracker
+func newCheckpointRunTracker() *checkpointRunTracker {
+	return &checkpointRunTracker{
+		runs: make(map[types.NamespacedName][]*checkpointRun),
+	}
+}
+
+// active returns the number of checkpoints currently running for the given CheckpointBackup
+func (t *checkpointRunTracker) active(key types.NamespacedName) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.runs[key])
+}
+
+// isRunning reports whether a checkpoint for the given schedule time is already running
+func (t *checkpointRunTracker) isRunning(key types.NamespacedName, scheduledTime time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, run := range t.runs[key] {
+		if run.scheduledTime.Equal(scheduledTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// start runs fn in the background for the given schedule time and tracks it until it returns
+func (t *checkpointRunTracker) start(key types.NamespacedName, scheduledTime time.Time, fn func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &checkpointRun{
+		scheduledTime: scheduledTime,
+		cancel:        cancel,
+	}
+
+	t.mu.Lock()
+	t.runs[key] = append(t.runs[key], run)
+	t.mu.Unlock()
+
+	go func() {
+		defer t.finish(key, run)
+		fn(ctx)
+	}()
+}
+
+// finish stops tracking a checkpoint run
+func (t *checkpointRunTracker) finish(key types.NamespacedName, run *checkpointRun) {
+	run.cancel()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	runs := t.runs[key]
+	for i := range runs {
+		if runs[i] == run {
+			t.runs[key] = append(runs[:i], runs[i+1:]...)
+			break
+		}
+	}
+	if len(t.runs[key]) == 0 {
+		delete(t.runs, key)
+	}
+}
+
+// cancel cancels every checkpoint running for the given CheckpointBackup
+func (t *checkpointRunTracker) cancel(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, run := range t.runs[key] {
+		run.cancel()
+	}
+}
+
+// cancelAll cancels every running checkpoint
+func (t *checkpointRunTracker) cancelAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, runs := range t.runs {
+		for _, run := range runs {
+			run.cancel()
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Checkpoint schedule", func() {
+	base := time.Date(2025, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+	Context("When parsing schedules", func() {
+		It("should accept standard and descriptor syntax", func() {
+			for _, schedule := range []string{"*/5 * * * *", "0 3 * * 1-5", "@hourly", "@every 90s"} {
+				_, err := parseSchedule(schedule)
+				Expect(err).NotTo(HaveOccurred(), schedule)
+			}
+		})
+
+		It("should reject invalid expressions", func() {
+			for _, schedule := range []string{"", "every five minutes", "* * * *", "61 * * * *"} {
+				_, err := parseSchedule(schedule)
+				Expect(err).To(HaveOccurred(), schedule)
+			}
+		})
+	})
+
+	Context("When computing the next schedule", func() {
+		It("should return no missed run before the first fire time", func() {
+			sched, err := parseSchedule("*/5 * * * *")
+			Expect(err).NotTo(HaveOccurred())
+
+			missed, next, err := getNextSchedule(sched, base, base.Add(2*time.Minute), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(missed.IsZero()).To(BeTrue())
+			Expect(next).To(Equal(base.Add(5 * time.Minute)))
+		})
+
+		It("should return the most recent missed run", func() {
+			sched, err := parseSchedule("@every 1m")
+			Expect(err).NotTo(HaveOccurred())
+
+			missed, next, err := getNextSchedule(sched, base, base.Add(3*time.Minute+30*time.Second), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(missed).To(Equal(base.Add(3 * time.Minute)))
+			Expect(next).To(Equal(base.Add(4*time.Minute + 30*time.Second)))
+		})
+
+		It("should skip runs older than the starting deadline", func() {
+			sched, err := parseSchedule("@hourly")
+			Expect(err).NotTo(HaveOccurred())
+
+			now := base.Add(90 * time.Minute)
+			missed, _, err := getNextSchedule(sched, base, now, ptr.To[int64](600))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(missed.IsZero()).To(BeTrue())
+
+			missed, _, err = getNextSchedule(sched, base, now, ptr.To[int64](3600))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(missed).To(Equal(base.Add(time.Hour)))
+		})
+
+		It("should fail when too many runs were missed", func() {
+			sched, err := parseSchedule("* * * * *")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = getNextSchedule(sched, base, base.Add(24*time.Hour), nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When tracking checkpoint runs", func() {
+		It("should track and cancel running checkpoints", func() {
+			tracker := newCheckpointRunTracker()
+			key := types.NamespacedName{Namespace: "default", Name: "backup"}
+
+			started := make(chan struct{})
+			stopped := make(chan struct{})
+			tracker.start(key, base, func(ctx context.Context) {
+				close(started)
+				<-ctx.Done()
+				close(stopped)
+			})
+
+			Eventually(started).Should(BeClosed())
+			Expect(tracker.active(key)).To(Equal(1))
+			Expect(tracker.isRunning(key, base)).To(BeTrue())
+			Expect(tracker.isRunning(key, base.Add(time.Minute))).To(BeFalse())
+
+			tracker.cancel(key)
+			Eventually(stopped).Should(BeClosed())
+			Eventually(func() int { return tracker.active(key) }).Should(BeZero())
+		})
+	})
+})
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
+	"github.com/karmada-io/karmada/pkg/util/names"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// sourceClusters returns the clusters the StatefulMigration backs up from, as last resolved from the
+// ResourceBindings of its workloads, or Spec.SourceClusters until they are resolved
+func sourceClusters(statefulMigration *migrationv1.StatefulMigration) []string {
+	if len(statefulMigration.Status.SourceClusters) == 0 {
+		return statefulMigration.Spec.SourceClusters
+	}
+	return sourceClusterNames(statefulMigration.Status.SourceClusters)
+}
+
+// sourceClusterNames returns the names of the source clusters
+func sourceClusterNames(clusters []migrationv1.SourceCluster) []string {
+	clusterNames := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		clusterNames = append(clusterNames, cluster.Name)
+	}
+	return clusterNames
+}
+
+// bindingClusters returns the clusters Karmada scheduled the workload of the binding to, with their replicas,
+// keeping only the allowed clusters if any are given
+func bindingClusters(binding *workv1alpha2.ResourceBinding, allowed []string) []migrationv1.SourceCluster {
+	var clusters []migrationv1.SourceCluster
+	for _, target := range binding.Spec.Clusters {
+		if len(allowed) == 0 || slices.Contains(allowed, target.Name) {
+			clusters = append(clusters, migrationv1.SourceCluster{Name: target.Name, Replicas: target.Replicas})
+		}
+	}
+	return clusters
+}
+
+// mergeSourceClusters adds up the replicas of the clusters of several workloads, sorted by name
+func mergeSourceClusters(clusters []migrationv1.SourceCluster) []migrationv1.SourceCluster {
+	var merged []migrationv1.SourceCluster
+	for _, cluster := range clusters {
+		index := slices.IndexFunc(merged, func(existing migrationv1.SourceCluster) bool { return existing.Name == cluster.Name })
+		if index < 0 {
+			merged = append(merged, cluster)
+			continue
+		}
+		merged[index].Replicas += cluster.Replicas
+	}
+	slices.SortFunc(merged, func(a, b migrationv1.SourceCluster) int { return strings.Compare(a.Name, b.Name) })
+	return merged
+}
+
+// reconcileSourceClusters resolves the clusters to back up the workloads from and records them in the status.
+// A workload is backed up on the clusters of its ResourceBinding allowed by Spec.SourceClusters, or on
+// Spec.SourceClusters if it has no ResourceBinding, like bare pods created on the member clusters.
+func (r *MigrationBackupReconciler) reconcileSourceClusters(ctx context.Context, statefulMigration *migrationv1.StatefulMigration, workloads []migrationv1.ResourceRef) ([]string, error) {
+	log := logf.FromContext(ctx)
+
+	var clusters []migrationv1.SourceCluster
+	for _, workload := range workloads {
+		binding := &workv1alpha2.ResourceBinding{}
+		key := types.NamespacedName{
+			Name:      names.GenerateBindingName(workload.Kind, workload.Name),
+			Namespace: cmp.Or(workload.Namespace, statefulMigration.Namespace),
+		}
+		if err := r.Get(ctx, key, binding); err != nil {
+			if !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return nil, fmt.Errorf("failed to get ResourceBinding of %s %s: %w", workload.Kind, workload.Name, err)
+			}
+			log.V(1).Info("Workload has no ResourceBinding, using the listed source clusters", "kind", workload.Kind, "name", workload.Name)
+			for _, cluster := range statefulMigration.Spec.SourceClusters {
+				clusters = append(clusters, migrationv1.SourceCluster{Name: cluster})
+			}
+			continue
+		}
+		clusters = append(clusters, bindingClusters(binding, statefulMigration.Spec.SourceClusters)...)
+	}
+
+	statefulMigration.Status.SourceClusters = mergeSourceClusters(clusters)
+	return sourceClusterNames(statefulMigration.Status.SourceClusters), nil
+}
+
+// findMigrationsForBinding maps a ResourceBinding to the StatefulMigrations enrolling its workload, so that
+// backups follow the workload when Karmada reschedules it
+func (r *MigrationBackupReconciler) findMigrationsForBinding(ctx context.Context, obj client.Object) []reconcile.Request {
+	binding, ok := obj.(*workv1alpha2.ResourceBinding)
+	if !ok {
+		return nil
+	}
+
+	resource := binding.Spec.Resource
+	var statefulMigrations migrationv1.StatefulMigrationList
+	if err := r.List(ctx, &statefulMigrations, client.MatchingFields{
+		workloadIndexKey: workloadIndexValue(resource.Kind, resource.Namespace, resource.Name),
+	}); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list StatefulMigrations enrolling workload", "kind", resource.Kind, "name", resource.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range statefulMigrations.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&statefulMigrations.Items[i])})
+	}
+	return requests
+}
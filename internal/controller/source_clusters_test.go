@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+var _ = Describe("Source clusters", func() {
+	ctx := context.Background()
+
+	binding := func(clusters ...workv1alpha2.TargetCluster) *workv1alpha2.ResourceBinding {
+		return &workv1alpha2.ResourceBinding{Spec: workv1alpha2.ResourceBindingSpec{Clusters: clusters}}
+	}
+
+	Context("When reading the clusters of a ResourceBinding", func() {
+		It("should keep the scheduled clusters allowed by the StatefulMigration", func() {
+			scheduled := binding(
+				workv1alpha2.TargetCluster{Name: "member1", Replicas: 2},
+				workv1alpha2.TargetCluster{Name: "member2", Replicas: 1},
+			)
+			Expect(bindingClusters(scheduled, nil)).To(Equal([]migrationv1.SourceCluster{
+				{Name: "member1", Replicas: 2},
+				{Name: "member2", Replicas: 1},
+			}))
+			Expect(bindingClusters(scheduled, []string{"member2", "member3"})).To(Equal([]migrationv1.SourceCluster{
+				{Name: "member2", Replicas: 1},
+			}))
+		})
+
+		It("should add up the replicas of the workloads on each cluster", func() {
+			Expect(mergeSourceClusters([]migrationv1.SourceCluster{
+				{Name: "member2", Replicas: 1},
+				{Name: "member1", Replicas: 2},
+				{Name: "member2", Replicas: 3},
+			})).To(Equal([]migrationv1.SourceCluster{
+				{Name: "member1", Replicas: 2},
+				{Name: "member2", Replicas: 4},
+			}))
+		})
+	})
+
+	Context("When resolving the source clusters", func() {
+		It("should fall back to the listed clusters until they are resolved", func() {
+			statefulMigration := &migrationv1.StatefulMigration{
+				Spec: migrationv1.StatefulMigrationSpec{SourceClusters: []string{"member1"}},
+			}
+			Expect(sourceClusters(statefulMigration)).To(Equal([]string{"member1"}))
+
+			statefulMigration.Status.SourceClusters = []migrationv1.SourceCluster{{Name: "member2", Replicas: 1}}
+			Expect(sourceClusters(statefulMigration)).To(Equal([]string{"member2"}))
+		})
+
+		It("should use the listed clusters for workloads without a ResourceBinding", func() {
+			statefulMigration := &migrationv1.StatefulMigration{}
+			statefulMigration.Namespace = "default"
+			statefulMigration.Spec.SourceClusters = []string{"member2", "member1"}
+
+			reconciler := &MigrationBackupReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			clusters, err := reconciler.reconcileSourceClusters(ctx, statefulMigration, []migrationv1.ResourceRef{
+				{APIVersion: "v1", Kind: "Pod", Name: "unbound"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusters).To(Equal([]string{"member1", "member2"}))
+			Expect(statefulMigration.Status.SourceClusters).To(Equal([]migrationv1.SourceCluster{
+				{Name: "member1"},
+				{Name: "member2"},
+			}))
+		})
+	})
+})
@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// statefulSetOrdinal returns the ordinal of a pod of the StatefulSet from the pod's name,
+// or false if the name does not belong to the StatefulSet
+func statefulSetOrdinal(statefulSetName, podName string) (int32, bool) {
+	suffix, found := strings.CutPrefix(podName, statefulSetName+"-")
+	if !found {
+		return 0, false
+	}
+	ordinal, err := strconv.ParseInt(suffix, 10, 32)
+	if err != nil || ordinal < 0 || strconv.FormatInt(ordinal, 10) != suffix {
+		return 0, false
+	}
+	return int32(ordinal), true
+}
+
+// statefulSetClaimName returns the name of the PersistentVolumeClaim of a StatefulSet ordinal,
+// following the naming of the StatefulSet controller
+func statefulSetClaimName(claim *corev1.PersistentVolumeClaim, identity *migrationv1.StatefulSetIdentity) string {
+	return fmt.Sprintf("%s-%s-%d", claim.Name, identity.Name, identity.Ordinal)
+}
+
+// applyStatefulSetIdentity gives the pod the labels, hostname and volumes the StatefulSet controller
+// sets on the pod of the ordinal, so that the StatefulSet adopts it as its own
+func applyStatefulSetIdentity(pod *corev1.Pod, identity *migrationv1.StatefulSetIdentity) {
+	pod.Labels[appsv1.StatefulSetPodNameLabel] = pod.Name
+	pod.Labels[appsv1.PodIndexLabel] = strconv.Itoa(int(identity.Ordinal))
+	if identity.RevisionHash != "" {
+		pod.Labels[appsv1.StatefulSetRevisionLabel] = identity.RevisionHash
+	}
+
+	pod.Spec.Hostname = pod.Name
+	pod.Spec.Subdomain = identity.ServiceName
+
+	for i := range identity.VolumeClaimTemplates {
+		claim := &identity.VolumeClaimTemplates[i]
+		volume := corev1.Volume{
+			Name: claim.Name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: statefulSetClaimName(claim, identity),
+				},
+			},
+		}
+
+		replaced := false
+		for j := range pod.Spec.Volumes {
+			if pod.Spec.Volumes[j].Name == claim.Name {
+				pod.Spec.Volumes[j] = volume
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+		}
+	}
+}
+
+// ensureStatefulSetClaims creates the PersistentVolumeClaims of the StatefulSet ordinal that do not exist yet.
+// The claims are not owned by the CheckpointRestore, so that they outlive it like the StatefulSet's own claims.
+func ensureStatefulSetClaims(ctx context.Context, c client.Client, namespace string, labels map[string]string, identity *migrationv1.StatefulSetIdentity) error {
+	for i := range identity.VolumeClaimTemplates {
+		template := &identity.VolumeClaimTemplates[i]
+
+		claim := &corev1.PersistentVolumeClaim{}
+		claim.Name = statefulSetClaimName(template, identity)
+		claim.Namespace = namespace
+		claim.Labels = make(map[string]string)
+		for key, value := range labels {
+			claim.Labels[key] = value
+		}
+		for key, value := range template.Labels {
+			claim.Labels[key] = value
+		}
+		claim.Annotations = template.Annotations
+		claim.Spec = *template.Spec.DeepCopy()
+
+		if err := c.Create(ctx, claim); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create PersistentVolumeClaim %s: %w", claim.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// maxOwnerDepth bounds how many controller owner references are followed from a pod to its workload,
+// such as Pod -> ReplicaSet -> Deployment -> Rollout
+const maxOwnerDepth = 4
+
+// isPodResource reports whether the resource reference points to a single pod, which only exists on the
+// member clusters rather than as a resource template on Karmada
+func isPodResource(resourceRef migrationv1.ResourceRef) bool {
+	return strings.EqualFold(resourceRef.Kind, "pod")
+}
+
+// enrolledWorkloads returns the workloads of the StatefulMigration: its ResourceRef, or the workloads
+// enrolled by its WorkloadSelector as recorded in its status
+func enrolledWorkloads(statefulMigration *migrationv1.StatefulMigration) []migrationv1.ResourceRef {
+	if statefulMigration.Spec.WorkloadSelector == nil {
+		return []migrationv1.ResourceRef{statefulMigration.Spec.ResourceRef}
+	}
+	return statefulMigration.Status.Workloads
+}
+
+// describeWorkloads describes the workloads of the StatefulMigration in status messages
+func describeWorkloads(statefulMigration *migrationv1.StatefulMigration) string {
+	if statefulMigration.Spec.WorkloadSelector == nil {
+		return fmt.Sprintf("%s %s", statefulMigration.Spec.ResourceRef.Kind, statefulMigration.Spec.ResourceRef.Name)
+	}
+	return fmt.Sprintf("%d selected workload(s)", len(statefulMigration.Status.Workloads))
+}
+
+// selectWorkloads lists the StatefulSets, Deployments and Pods matching the WorkloadSelector, in the namespaces
+// matching its namespace selector or else in the namespace of the StatefulMigration. Pods controlled by another
+// workload are left out, since they are enrolled through their workload.
+func selectWorkloads(ctx context.Context, c client.Client, statefulMigration *migrationv1.StatefulMigration) ([]migrationv1.ResourceRef, error) {
+	enrollment := statefulMigration.Spec.WorkloadSelector
+
+	selector, err := metav1.LabelSelectorAsSelector(&enrollment.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workload label selector: %w", err)
+	}
+
+	namespaces := []string{statefulMigration.Namespace}
+	if enrollment.NamespaceSelector != nil {
+		namespaceSelector, err := metav1.LabelSelectorAsSelector(enrollment.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid workload namespace selector: %w", err)
+		}
+		var namespaceList corev1.NamespaceList
+		if err := c.List(ctx, &namespaceList, client.MatchingLabelsSelector{Selector: namespaceSelector}); err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+		namespaces = nil
+		for _, namespace := range namespaceList.Items {
+			namespaces = append(namespaces, namespace.Name)
+		}
+	}
+
+	var workloads []migrationv1.ResourceRef
+	for _, namespace := range namespaces {
+		listOptions := []client.ListOption{client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}}
+
+		var statefulSets appsv1.StatefulSetList
+		if err := c.List(ctx, &statefulSets, listOptions...); err != nil {
+			return nil, fmt.Errorf("failed to list StatefulSets in %s: %w", namespace, err)
+		}
+		for _, statefulSet := range statefulSets.Items {
+			workloads = append(workloads, migrationv1.ResourceRef{APIVersion: "apps/v1", Kind: "StatefulSet", Namespace: namespace, Name: statefulSet.Name})
+		}
+
+		var deployments appsv1.DeploymentList
+		if err := c.List(ctx, &deployments, listOptions...); err != nil {
+			return nil, fmt.Errorf("failed to list Deployments in %s: %w", namespace, err)
+		}
+		for _, deployment := range deployments.Items {
+			workloads = append(workloads, migrationv1.ResourceRef{APIVersion: "apps/v1", Kind: "Deployment", Namespace: namespace, Name: deployment.Name})
+		}
+
+		var pods corev1.PodList
+		if err := c.List(ctx, &pods, listOptions...); err != nil {
+			return nil, fmt.Errorf("failed to list Pods in %s: %w", namespace, err)
+		}
+		for i := range pods.Items {
+			if metav1.GetControllerOfNoCopy(&pods.Items[i]) == nil {
+				workloads = append(workloads, migrationv1.ResourceRef{APIVersion: "v1", Kind: "Pod", Namespace: namespace, Name: pods.Items[i].Name})
+			}
+		}
+	}
+
+	slices.SortFunc(workloads, func(a, b migrationv1.ResourceRef) int {
+		return cmp.Or(strings.Compare(a.Namespace, b.Namespace), strings.Compare(a.Kind, b.Kind), strings.Compare(a.Name, b.Name))
+	})
+	return workloads, nil
+}
+
+// resourceMapping resolves the kind of the resource reference through the RESTMapper. The kind is
+// matched case-insensitively, so both StatefulSet and statefulset are accepted.
+func resourceMapping(mapper meta.RESTMapper, resourceRef migrationv1.ResourceRef) (*meta.RESTMapping, error) {
+	gv, err := schema.ParseGroupVersion(resourceRef.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apiVersion %q: %w", resourceRef.APIVersion, err)
+	}
+
+	gvk, err := mapper.KindFor(gv.WithResource(strings.ToLower(resourceRef.Kind)))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported resource kind %s in %s: %w", resourceRef.Kind, resourceRef.APIVersion, err)
+	}
+	return mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+}
+
+// getWorkload reads the workload of the resource reference with the unstructured client, so that any
+// workload kind known to the API server can be migrated
+func getWorkload(ctx context.Context, c client.Client, resourceRef migrationv1.ResourceRef) (*unstructured.Unstructured, error) {
+	mapping, err := resourceMapping(c.RESTMapper(), resourceRef)
+	if err != nil {
+		return nil, err
+	}
+
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(mapping.GroupVersionKind)
+	key := types.NamespacedName{Name: resourceRef.Name}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		key.Namespace = resourceRef.Namespace
+	}
+	if err := c.Get(ctx, key, workload); err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", mapping.GroupVersionKind.Kind, resourceRef.Name, err)
+	}
+	return workload, nil
+}
+
+// workloadSelector returns the selector of the workload's pods from its spec.selector, or else from the
+// status of its scale subresource for custom workloads that do not follow the apps conventions
+func workloadSelector(ctx context.Context, c client.Client, workload *unstructured.Unstructured) (labels.Selector, error) {
+	selector, found, err := selectorFromSpec(workload)
+	if err != nil || found {
+		return selector, err
+	}
+
+	scale := &unstructured.Unstructured{}
+	scale.SetGroupVersionKind(schema.GroupVersionKind{Group: "autoscaling", Version: "v1", Kind: "Scale"})
+	if err := c.SubResource("scale").Get(ctx, workload, scale); err != nil {
+		return nil, fmt.Errorf("%s %s has no spec.selector and no scale subresource: %w", workload.GetKind(), workload.GetName(), err)
+	}
+	scaleSelector, _, _ := unstructured.NestedString(scale.Object, "status", "selector")
+	if scaleSelector == "" {
+		return nil, fmt.Errorf("the scale subresource of %s %s reports no selector", workload.GetKind(), workload.GetName())
+	}
+	return labels.Parse(scaleSelector)
+}
+
+// selectorFromSpec reads spec.selector of a workload, either as a label selector like the apps workloads
+// or as a map of labels like a ReplicationController
+func selectorFromSpec(workload *unstructured.Unstructured) (labels.Selector, bool, error) {
+	value, found, err := unstructured.NestedFieldNoCopy(workload.Object, "spec", "selector")
+	if err != nil || !found || value == nil {
+		return nil, false, err
+	}
+
+	switch selector := value.(type) {
+	case string:
+		parsed, err := labels.Parse(selector)
+		return parsed, true, err
+
+	case map[string]interface{}:
+		_, hasMatchLabels := selector["matchLabels"]
+		_, hasMatchExpressions := selector["matchExpressions"]
+		if !hasMatchLabels && !hasMatchExpressions && len(selector) > 0 {
+			matchLabels, _, err := unstructured.NestedStringMap(workload.Object, "spec", "selector")
+			if err != nil {
+				return nil, false, err
+			}
+			return labels.SelectorFromSet(matchLabels), true, nil
+		}
+
+		var labelSelector metav1.LabelSelector
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selector, &labelSelector); err != nil {
+			return nil, false, fmt.Errorf("invalid spec.selector of %s %s: %w", workload.GetKind(), workload.GetName(), err)
+		}
+		parsed, err := metav1.LabelSelectorAsSelector(&labelSelector)
+		return parsed, true, err
+
+	default:
+		return nil, false, fmt.Errorf("unsupported spec.selector of %s %s", workload.GetKind(), workload.GetName())
+	}
+}
+
+// workloadPodTemplate returns the pod template of the workload from its spec.template
+func workloadPodTemplate(workload *unstructured.Unstructured) (*corev1.PodTemplateSpec, error) {
+	template, found, err := unstructured.NestedMap(workload.Object, "spec", "template")
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%s %s has no spec.template", workload.GetKind(), workload.GetName())
+	}
+
+	var podTemplate corev1.PodTemplateSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template, &podTemplate); err != nil {
+		return nil, fmt.Errorf("invalid spec.template of %s %s: %w", workload.GetKind(), workload.GetName(), err)
+	}
+	return &podTemplate, nil
+}
+
+// ownerReferenceMatches reports whether the owner reference points to the workload. Owners are matched by
+// group, kind and name since the workload has another UID on each member cluster than on Karmada.
+func ownerReferenceMatches(ref *metav1.OwnerReference, workload *unstructured.Unstructured) bool {
+	refGV, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false
+	}
+	return refGV.Group == workload.GroupVersionKind().Group && ref.Kind == workload.GetKind() && ref.Name == workload.GetName()
+}
+
+// isOwnedByWorkload follows the controller owner references of the pod up to the workload, reading each
+// intermediate owner, such as the ReplicaSet of a Deployment, with getOwner. getOwner returns nil if the
+// owner does not exist.
+func isOwnedByWorkload(pod *corev1.Pod, workload *unstructured.Unstructured,
+	getOwner func(ref *metav1.OwnerReference) (*unstructured.Unstructured, error)) (bool, error) {
+	ref := metav1.GetControllerOfNoCopy(pod)
+	for depth := 0; ref != nil && depth < maxOwnerDepth; depth++ {
+		if ownerReferenceMatches(ref, workload) {
+			return true, nil
+		}
+
+		owner, err := getOwner(ref)
+		if err != nil || owner == nil {
+			return false, err
+		}
+		ref = metav1.GetControllerOfNoCopy(owner)
+	}
+	return false, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// WorkloadMarkerLabel marks enrolled workloads with the CheckpointMigrationLabel label
+	WorkloadMarkerLabel = "label"
+	// WorkloadMarkerAnnotation marks enrolled workloads with an annotation of the same key instead,
+	// for workloads managed by GitOps tools that would report the label as drift
+	WorkloadMarkerAnnotation = "annotation"
+)
+
+// ParseWorkloadMarker validates the kind of marker put on enrolled workloads
+func ParseWorkloadMarker(marker string) (string, error) {
+	switch marker {
+	case "", WorkloadMarkerLabel:
+		return WorkloadMarkerLabel, nil
+	case WorkloadMarkerAnnotation:
+		return WorkloadMarkerAnnotation, nil
+	}
+	return "", fmt.Errorf("unsupported workload marker %q, must be %q or %q", marker, WorkloadMarkerLabel, WorkloadMarkerAnnotation)
+}
+
+// hasWorkloadMarker reports whether the object carries the given marker and not the other one
+func hasWorkloadMarker(obj metav1.Object, marker string) bool {
+	labelValue, labeled := obj.GetLabels()[CheckpointMigrationLabel]
+	annotationValue, annotated := obj.GetAnnotations()[CheckpointMigrationLabel]
+	if marker == WorkloadMarkerAnnotation {
+		return annotationValue == "true" && !labeled
+	}
+	return labelValue == "true" && !annotated
+}
+
+// hasAnyWorkloadMarker reports whether the object carries the label or the annotation
+func hasAnyWorkloadMarker(obj metav1.Object) bool {
+	_, labeled := obj.GetLabels()[CheckpointMigrationLabel]
+	_, annotated := obj.GetAnnotations()[CheckpointMigrationLabel]
+	return labeled || annotated
+}
+
+// workloadMarkerPatch returns the JSON merge patch that puts the marker on a workload, or removes both markers
+// if enrolled is false. The patch only touches the CheckpointMigrationLabel key, so it neither conflicts with
+// nor overwrites the changes of other controllers, and the marker not in use is removed when switching kinds.
+func workloadMarkerPatch(marker string, enrolled bool) ([]byte, error) {
+	labels := map[string]interface{}{CheckpointMigrationLabel: nil}
+	annotations := map[string]interface{}{CheckpointMigrationLabel: nil}
+	if enrolled {
+		if marker == WorkloadMarkerAnnotation {
+			annotations[CheckpointMigrationLabel] = "true"
+		} else {
+			labels[CheckpointMigrationLabel] = "true"
+		}
+	}
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      labels,
+			"annotations": annotations,
+		},
+	})
+}
+
+// workloadMarkerVerb describes the marker in events
+func workloadMarkerVerb(marker string) string {
+	if marker == WorkloadMarkerAnnotation {
+		return "Annotated"
+	}
+	return "Labeled"
+}
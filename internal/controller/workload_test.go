@@ -0,0 +1,248 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+var _ = Describe("Workload resolver", func() {
+	newWorkload := func(apiVersion, kind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+			"spec":       spec,
+		}}
+	}
+
+	Context("When reading a workload", func() {
+		ctx := context.Background()
+
+		It("should resolve the kind case-insensitively and read its selector and template", func() {
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "resolver-web", Namespace: "default"},
+				Spec: appsv1.StatefulSetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "resolver-web"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "resolver-web"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "web", Image: "nginx:1.27"}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, statefulSet)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, statefulSet)).To(Succeed())
+			})
+
+			workload, err := getWorkload(ctx, k8sClient, migrationv1.ResourceRef{
+				APIVersion: "apps/v1",
+				Kind:       "statefulset",
+				Namespace:  "default",
+				Name:       "resolver-web",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(workload.GetKind()).To(Equal("StatefulSet"))
+
+			selector, err := workloadSelector(ctx, k8sClient, workload)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector.Matches(labels.Set{"app": "resolver-web"})).To(BeTrue())
+
+			template, err := workloadPodTemplate(workload)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(template.Spec.Containers[0].Image).To(Equal("nginx:1.27"))
+		})
+
+		It("should reject unknown kinds", func() {
+			_, err := getWorkload(ctx, k8sClient, migrationv1.ResourceRef{
+				APIVersion: "apps/v1",
+				Kind:       "CronTab",
+				Namespace:  "default",
+				Name:       "resolver-web",
+			})
+			Expect(err).To(MatchError(ContainSubstring("unsupported resource kind")))
+		})
+	})
+
+	Context("When selecting workloads", func() {
+		ctx := context.Background()
+
+		It("should enroll the matching StatefulSets, Deployments and bare Pods", func() {
+			selected := map[string]string{"backup": "enabled"}
+			template := corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "selected"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "busybox:1.36"}}},
+			}
+			selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "selected"}}
+
+			objects := []client.Object{
+				&appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "selected-db", Namespace: "default", Labels: selected},
+					Spec:       appsv1.StatefulSetSpec{Selector: selector, Template: template},
+				},
+				&appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "unselected-db", Namespace: "default"},
+					Spec:       appsv1.StatefulSetSpec{Selector: selector, Template: template},
+				},
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "selected-api", Namespace: "default", Labels: selected},
+					Spec:       appsv1.DeploymentSpec{Selector: selector, Template: template},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "selected-job", Namespace: "default", Labels: selected},
+					Spec:       template.Spec,
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "selected-db-0", Namespace: "default", Labels: selected,
+						OwnerReferences: []metav1.OwnerReference{{
+							APIVersion: "apps/v1", Kind: "StatefulSet", Name: "selected-db", UID: types.UID("uid-selected-db"), Controller: ptr.To(true),
+						}},
+					},
+					Spec: template.Spec,
+				},
+			}
+			for _, obj := range objects {
+				Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+			}
+			DeferCleanup(func() {
+				for _, obj := range objects {
+					Expect(k8sClient.Delete(ctx, obj)).To(Succeed())
+				}
+			})
+
+			statefulMigration := &migrationv1.StatefulMigration{
+				ObjectMeta: metav1.ObjectMeta{Name: "selected", Namespace: "default"},
+				Spec: migrationv1.StatefulMigrationSpec{
+					WorkloadSelector: &migrationv1.WorkloadSelector{
+						LabelSelector: metav1.LabelSelector{MatchLabels: selected},
+					},
+				},
+			}
+
+			workloads, err := selectWorkloads(ctx, k8sClient, statefulMigration)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(workloads).To(Equal([]migrationv1.ResourceRef{
+				{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "selected-api"},
+				{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "selected-job"},
+				{APIVersion: "apps/v1", Kind: "StatefulSet", Namespace: "default", Name: "selected-db"},
+			}))
+
+			statefulMigration.Status.Workloads = workloads
+			Expect(enrolledWorkloads(statefulMigration)).To(HaveLen(3))
+			Expect(describeWorkloads(statefulMigration)).To(Equal("3 selected workload(s)"))
+		})
+
+		It("should name backups after the pod namespace when selecting namespaces", func() {
+			statefulMigration := &migrationv1.StatefulMigration{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenants", Namespace: "default"},
+				Spec: migrationv1.StatefulMigrationSpec{
+					WorkloadSelector: &migrationv1.WorkloadSelector{},
+				},
+			}
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "tenant-a"}}
+			Expect(checkpointBackupName(statefulMigration, pod, "member1")).To(Equal("tenants-db-0-member1"))
+
+			statefulMigration.Spec.WorkloadSelector.NamespaceSelector = &metav1.LabelSelector{}
+			Expect(checkpointBackupName(statefulMigration, pod, "member1")).To(Equal("tenants-tenant-a-db-0-member1"))
+		})
+	})
+
+	Context("When reading the selector from the spec", func() {
+		It("should accept label selectors and label maps", func() {
+			selector, found, err := selectorFromSpec(newWorkload("apps/v1", "DaemonSet", "agent", map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchExpressions": []interface{}{
+						map[string]interface{}{"key": "tier", "operator": "In", "values": []interface{}{"edge", "core"}},
+					},
+				},
+			}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(selector.Matches(labels.Set{"tier": "edge"})).To(BeTrue())
+			Expect(selector.Matches(labels.Set{"tier": "cloud"})).To(BeFalse())
+
+			selector, found, err = selectorFromSpec(newWorkload("v1", "ReplicationController", "legacy", map[string]interface{}{
+				"selector": map[string]interface{}{"app": "legacy"},
+			}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(selector.String()).To(Equal("app=legacy"))
+
+			_, found, err = selectorFromSpec(newWorkload("example.com/v1", "Database", "db", map[string]interface{}{}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Context("When following owner references", func() {
+		deployment := newWorkload("apps/v1", "Deployment", "api", nil)
+
+		newOwnerReference := func(apiVersion, kind, name string) metav1.OwnerReference {
+			return metav1.OwnerReference{APIVersion: apiVersion, Kind: kind, Name: name, UID: types.UID("uid-" + name), Controller: ptr.To(true)}
+		}
+
+		It("should find the Deployment through its ReplicaSet", func() {
+			replicaSet := newWorkload("apps/v1", "ReplicaSet", "api-7d4b9c", nil)
+			replicaSet.SetOwnerReferences([]metav1.OwnerReference{newOwnerReference("apps/v1", "Deployment", "api")})
+
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            "api-7d4b9c-x2x8p",
+				OwnerReferences: []metav1.OwnerReference{newOwnerReference("apps/v1", "ReplicaSet", "api-7d4b9c")},
+			}}
+
+			owned, err := isOwnedByWorkload(pod, deployment, func(ref *metav1.OwnerReference) (*unstructured.Unstructured, error) {
+				Expect(ref.Name).To(Equal("api-7d4b9c"))
+				return replicaSet, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(owned).To(BeTrue())
+		})
+
+		It("should skip pods of other workloads matching the selector", func() {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            "api-canary-0",
+				OwnerReferences: []metav1.OwnerReference{newOwnerReference("apps/v1", "StatefulSet", "api-canary")},
+			}}
+
+			owned, err := isOwnedByWorkload(pod, deployment, func(*metav1.OwnerReference) (*unstructured.Unstructured, error) {
+				return nil, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(owned).To(BeFalse())
+
+			owned, err = isOwnedByWorkload(&corev1.Pod{}, deployment, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(owned).To(BeFalse())
+		})
+	})
+})
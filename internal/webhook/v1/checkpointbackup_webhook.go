@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var checkpointbackuplog = logf.Log.WithName("checkpointbackup-resource")
+
+// SetupCheckpointBackupWebhookWithManager registers the webhook for CheckpointBackup in the manager.
+func SetupCheckpointBackupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&migrationv1.CheckpointBackup{}).
+		WithValidator(&CheckpointBackupCustomValidator{Reader: mgr.GetAPIReader(), Mapper: mgr.GetRESTMapper()}).
+		WithDefaulter(&CheckpointBackupCustomDefaulter{Mapper: mgr.GetRESTMapper()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-migration-dcnlab-com-v1-checkpointbackup,mutating=true,failurePolicy=fail,sideEffects=None,groups=migration.dcnlab.com,resources=checkpointbackups,verbs=create;update,versions=v1,name=mcheckpointbackup-v1.kb.io,admissionReviewVersions=v1
+
+// CheckpointBackupCustomDefaulter sets default values on the ResourceRef and PodRef of a CheckpointBackup
+// when it is created or updated.
+type CheckpointBackupCustomDefaulter struct {
+	Mapper meta.RESTMapper
+}
+
+var _ webhook.CustomDefaulter = &CheckpointBackupCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind CheckpointBackup.
+func (d *CheckpointBackupCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	checkpointbackup, ok := obj.(*migrationv1.CheckpointBackup)
+	if !ok {
+		return fmt.Errorf("expected a CheckpointBackup object but got %T", obj)
+	}
+	checkpointbackuplog.Info("Defaulting for CheckpointBackup", "name", checkpointbackup.GetName())
+
+	defaultResourceRef(d.Mapper, &checkpointbackup.Spec.ResourceRef, checkpointbackup.Namespace)
+	if checkpointbackup.Spec.PodRef.Namespace == "" {
+		checkpointbackup.Spec.PodRef.Namespace = checkpointbackup.Namespace
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-migration-dcnlab-com-v1-checkpointbackup,mutating=false,failurePolicy=fail,sideEffects=None,groups=migration.dcnlab.com,resources=checkpointbackups,verbs=create;update,versions=v1,name=vcheckpointbackup-v1.kb.io,admissionReviewVersions=v1
+
+// CheckpointBackupCustomValidator validates a CheckpointBackup when it is created or updated.
+// It reads Secrets with Reader, which should not be backed by a cache.
+type CheckpointBackupCustomValidator struct {
+	Reader client.Reader
+	Mapper meta.RESTMapper
+}
+
+var _ webhook.CustomValidator = &CheckpointBackupCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type CheckpointBackup.
+func (v *CheckpointBackupCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	checkpointbackup, ok := obj.(*migrationv1.CheckpointBackup)
+	if !ok {
+		return nil, fmt.Errorf("expected a CheckpointBackup object but got %T", obj)
+	}
+	checkpointbackuplog.Info("Validation for CheckpointBackup upon creation", "name", checkpointbackup.GetName())
+
+	return nil, v.validateCheckpointBackup(ctx, checkpointbackup, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type CheckpointBackup.
+func (v *CheckpointBackupCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	checkpointbackup, ok := newObj.(*migrationv1.CheckpointBackup)
+	if !ok {
+		return nil, fmt.Errorf("expected a CheckpointBackup object for the newObj but got %T", newObj)
+	}
+	oldCheckpointBackup, ok := oldObj.(*migrationv1.CheckpointBackup)
+	if !ok {
+		return nil, fmt.Errorf("expected a CheckpointBackup object for the oldObj but got %T", oldObj)
+	}
+	checkpointbackuplog.Info("Validation for CheckpointBackup upon update", "name", checkpointbackup.GetName())
+
+	return nil, v.validateCheckpointBackup(ctx, checkpointbackup, oldCheckpointBackup)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type CheckpointBackup.
+func (v *CheckpointBackupCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateCheckpointBackup validates the spec of a CheckpointBackup. On update, the registry Secret is only
+// looked up if it changed.
+func (v *CheckpointBackupCustomValidator) validateCheckpointBackup(ctx context.Context, checkpointbackup, old *migrationv1.CheckpointBackup) error {
+	spec := checkpointbackup.Spec
+	specPath := field.NewPath("spec")
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateSchedule(spec.Schedule, specPath.Child("schedule"))...)
+	allErrs = append(allErrs, validateResourceRef(v.Mapper, spec.ResourceRef, specPath.Child("resourceRef"))...)
+	if spec.PodRef.Name == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("podRef", "name"), "the name of the pod to checkpoint is required"))
+	}
+	allErrs = append(allErrs, validateContainers(spec.Containers, specPath.Child("containers"))...)
+
+	var oldRegistry *migrationv1.Registry
+	if old != nil {
+		oldRegistry = &old.Spec.Registry
+	}
+	errs, err := validateRegistry(ctx, v.Reader, checkpointbackup.Namespace, spec.Registry, oldRegistry, specPath.Child("registry"))
+	if err != nil {
+		return err
+	}
+	allErrs = append(allErrs, errs...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(migrationv1.GroupVersion.WithKind("CheckpointBackup").GroupKind(), checkpointbackup.Name, allErrs)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+var _ = Describe("CheckpointBackup Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *migrationv1.CheckpointBackup
+		validator CheckpointBackupCustomValidator
+		defaulter CheckpointBackupCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &migrationv1.CheckpointBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-web-0-member1", Namespace: "default"},
+			Spec: migrationv1.CheckpointBackupSpec{
+				Schedule: "*/5 * * * *",
+				PodRef:   migrationv1.PodRef{Name: "web-0"},
+				ResourceRef: migrationv1.ResourceRef{
+					Kind: "StatefulSet",
+					Name: "web",
+				},
+				Registry: migrationv1.Registry{
+					URL:        "http://registry.example.com",
+					Repository: "checkpoints",
+				},
+				Containers: []migrationv1.Container{{Name: "app", Image: "app:1.0"}},
+			},
+		}
+		validator = CheckpointBackupCustomValidator{Reader: newFakeReader(), Mapper: mapper}
+		defaulter = CheckpointBackupCustomDefaulter{Mapper: mapper}
+	})
+
+	Context("When creating CheckpointBackup under Defaulting Webhook", func() {
+		It("Should fill in the namespaces and apiVersion", func() {
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.ResourceRef.Namespace).To(Equal("default"))
+			Expect(obj.Spec.ResourceRef.APIVersion).To(Equal("apps/v1"))
+			Expect(obj.Spec.PodRef.Namespace).To(Equal("default"))
+
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+	})
+
+	Context("When creating CheckpointBackup under Validating Webhook", func() {
+		It("Should deny invalid backups", func() {
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			obj.Spec.Schedule = "61 * * * *"
+			obj.Spec.PodRef.Name = ""
+			obj.Spec.Registry.URL = "https://user@registry.example.com"
+			obj.Spec.Registry.SecretRef = &migrationv1.SecretRef{Name: "registry-credentials"}
+			obj.Spec.Containers = append(obj.Spec.Containers, migrationv1.Container{Name: "app", Image: "app:1.1"})
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(And(
+				ContainSubstring("spec.schedule"),
+				ContainSubstring("spec.podRef.name"),
+				ContainSubstring("spec.registry.url"),
+				ContainSubstring("spec.registry.secretRef.name"),
+				ContainSubstring(`spec.containers[1].name: Duplicate value: "app"`),
+			))
+		})
+	})
+})
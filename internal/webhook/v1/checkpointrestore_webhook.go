@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var checkpointrestorelog = logf.Log.WithName("checkpointrestore-resource")
+
+// SetupCheckpointRestoreWebhookWithManager registers the webhook for CheckpointRestore in the manager.
+func SetupCheckpointRestoreWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&migrationv1.CheckpointRestore{}).
+		WithValidator(&CheckpointRestoreCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-migration-dcnlab-com-v1-checkpointrestore,mutating=false,failurePolicy=fail,sideEffects=None,groups=migration.dcnlab.com,resources=checkpointrestores,verbs=create;update,versions=v1,name=vcheckpointrestore-v1.kb.io,admissionReviewVersions=v1
+
+// CheckpointRestoreCustomValidator validates a CheckpointRestore when it is created or updated.
+type CheckpointRestoreCustomValidator struct{}
+
+var _ webhook.CustomValidator = &CheckpointRestoreCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type CheckpointRestore.
+func (v *CheckpointRestoreCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	checkpointrestore, ok := obj.(*migrationv1.CheckpointRestore)
+	if !ok {
+		return nil, fmt.Errorf("expected a CheckpointRestore object but got %T", obj)
+	}
+	checkpointrestorelog.Info("Validation for CheckpointRestore upon creation", "name", checkpointrestore.GetName())
+
+	return nil, validateCheckpointRestore(checkpointrestore)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type CheckpointRestore.
+func (v *CheckpointRestoreCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	checkpointrestore, ok := newObj.(*migrationv1.CheckpointRestore)
+	if !ok {
+		return nil, fmt.Errorf("expected a CheckpointRestore object for the newObj but got %T", newObj)
+	}
+	checkpointrestorelog.Info("Validation for CheckpointRestore upon update", "name", checkpointrestore.GetName())
+
+	return nil, validateCheckpointRestore(checkpointrestore)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type CheckpointRestore.
+func (v *CheckpointRestoreCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateCheckpointRestore validates the spec of a CheckpointRestore
+func validateCheckpointRestore(checkpointrestore *migrationv1.CheckpointRestore) error {
+	spec := checkpointrestore.Spec
+	specPath := field.NewPath("spec")
+	var allErrs field.ErrorList
+
+	if spec.BackupRef.Name == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("backupRef", "name"), "the CheckpointBackup to restore from is required"))
+	}
+	for _, msg := range validation.IsDNS1123Subdomain(spec.PodName) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("podName"), spec.PodName, msg))
+	}
+	allErrs = append(allErrs, validateContainers(spec.Containers, specPath.Child("containers"))...)
+
+	if identity := spec.StatefulSet; identity != nil {
+		for _, msg := range validation.IsDNS1123Subdomain(identity.Name) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("statefulSet", "name"), identity.Name, msg))
+		}
+	}
+	if identity := spec.Deployment; identity != nil {
+		if spec.StatefulSet != nil {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("deployment"), "a restored pod is handed over to either a StatefulSet or a Deployment"))
+		}
+		for _, msg := range validation.IsDNS1123Subdomain(identity.Name) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("deployment", "name"), identity.Name, msg))
+		}
+		if identity.PodTemplateHash == "" {
+			allErrs = append(allErrs, field.Required(specPath.Child("deployment", "podTemplateHash"), "the pod-template-hash of the original pod is required"))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(migrationv1.GroupVersion.WithKind("CheckpointRestore").GroupKind(), checkpointrestore.Name, allErrs)
+}
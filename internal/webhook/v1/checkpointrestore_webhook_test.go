@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+var _ = Describe("CheckpointRestore Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *migrationv1.CheckpointRestore
+		oldObj    *migrationv1.CheckpointRestore
+		validator CheckpointRestoreCustomValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &migrationv1.CheckpointRestore{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-0-restore", Namespace: "default"},
+			Spec: migrationv1.CheckpointRestoreSpec{
+				BackupRef: migrationv1.BackupRef{Name: "web-web-0-member1"},
+				PodName:   "web-0",
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = CheckpointRestoreCustomValidator{}
+	})
+
+	Context("When creating or updating CheckpointRestore under Validating Webhook", func() {
+		It("Should admit a valid CheckpointRestore", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny invalid pod names and missing backups", func() {
+			obj.Spec.BackupRef.Name = ""
+			obj.Spec.PodName = "Web_0"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(And(
+				ContainSubstring("spec.backupRef.name: Required value"),
+				ContainSubstring("spec.podName: Invalid value"),
+			)))
+		})
+
+		It("Should deny a pod handed over to both a StatefulSet and a Deployment", func() {
+			obj.Spec.StatefulSet = &migrationv1.StatefulSetIdentity{Name: "web"}
+			obj.Spec.Deployment = &migrationv1.DeploymentIdentity{Name: "web"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(And(
+				ContainSubstring("spec.deployment: Forbidden"),
+				ContainSubstring("spec.deployment.podTemplateHash: Required value"),
+			)))
+
+			obj.Spec.StatefulSet = nil
+			obj.Spec.Deployment.PodTemplateHash = "5d4f8b9c7"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+	})
+})
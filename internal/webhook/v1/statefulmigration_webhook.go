@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var statefulmigrationlog = logf.Log.WithName("statefulmigration-resource")
+
+// SetupStatefulMigrationWebhookWithManager registers the webhook for StatefulMigration in the manager.
+func SetupStatefulMigrationWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&migrationv1.StatefulMigration{}).
+		WithValidator(&StatefulMigrationCustomValidator{Reader: mgr.GetAPIReader(), Mapper: mgr.GetRESTMapper()}).
+		WithDefaulter(&StatefulMigrationCustomDefaulter{Mapper: mgr.GetRESTMapper()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-migration-dcnlab-com-v1-statefulmigration,mutating=true,failurePolicy=fail,sideEffects=None,groups=migration.dcnlab.com,resources=statefulmigrations,verbs=create;update,versions=v1,name=mstatefulmigration-v1.kb.io,admissionReviewVersions=v1
+
+// StatefulMigrationCustomDefaulter sets default values on the ResourceRef of a StatefulMigration
+// when it is created or updated.
+type StatefulMigrationCustomDefaulter struct {
+	Mapper meta.RESTMapper
+}
+
+var _ webhook.CustomDefaulter = &StatefulMigrationCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind StatefulMigration.
+func (d *StatefulMigrationCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	statefulmigration, ok := obj.(*migrationv1.StatefulMigration)
+	if !ok {
+		return fmt.Errorf("expected a StatefulMigration object but got %T", obj)
+	}
+	statefulmigrationlog.Info("Defaulting for StatefulMigration", "name", statefulmigration.GetName())
+
+	// A StatefulMigration selecting its workloads has no ResourceRef to default
+	if statefulmigration.Spec.WorkloadSelector == nil {
+		defaultResourceRef(d.Mapper, &statefulmigration.Spec.ResourceRef, statefulmigration.Namespace)
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-migration-dcnlab-com-v1-statefulmigration,mutating=false,failurePolicy=fail,sideEffects=None,groups=migration.dcnlab.com,resources=statefulmigrations,verbs=create;update,versions=v1,name=vstatefulmigration-v1.kb.io,admissionReviewVersions=v1
+
+// +kubebuilder:rbac:groups=cluster.karmada.io,resources=clusters,verbs=get
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// StatefulMigrationCustomValidator validates a StatefulMigration when it is created or updated.
+// It reads Karmada Clusters and Secrets with Reader, which should not be backed by a cache.
+type StatefulMigrationCustomValidator struct {
+	Reader client.Reader
+	Mapper meta.RESTMapper
+}
+
+var _ webhook.CustomValidator = &StatefulMigrationCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type StatefulMigration.
+func (v *StatefulMigrationCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	statefulmigration, ok := obj.(*migrationv1.StatefulMigration)
+	if !ok {
+		return nil, fmt.Errorf("expected a StatefulMigration object but got %T", obj)
+	}
+	statefulmigrationlog.Info("Validation for StatefulMigration upon creation", "name", statefulmigration.GetName())
+
+	return nil, v.validateStatefulMigration(ctx, statefulmigration, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type StatefulMigration.
+func (v *StatefulMigrationCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	statefulmigration, ok := newObj.(*migrationv1.StatefulMigration)
+	if !ok {
+		return nil, fmt.Errorf("expected a StatefulMigration object for the newObj but got %T", newObj)
+	}
+	oldStatefulMigration, ok := oldObj.(*migrationv1.StatefulMigration)
+	if !ok {
+		return nil, fmt.Errorf("expected a StatefulMigration object for the oldObj but got %T", oldObj)
+	}
+	statefulmigrationlog.Info("Validation for StatefulMigration upon update", "name", statefulmigration.GetName())
+
+	return nil, v.validateStatefulMigration(ctx, statefulmigration, oldStatefulMigration)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type StatefulMigration.
+func (v *StatefulMigrationCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateStatefulMigration validates the spec of a StatefulMigration. On update, the Karmada Clusters and
+// the registry Secret are only looked up if they changed.
+func (v *StatefulMigrationCustomValidator) validateStatefulMigration(ctx context.Context, statefulmigration, old *migrationv1.StatefulMigration) error {
+	spec := statefulmigration.Spec
+	specPath := field.NewPath("spec")
+	var allErrs field.ErrorList
+
+	if spec.WorkloadSelector != nil {
+		selectorPath := specPath.Child("workloadSelector")
+		allErrs = append(allErrs, validateLabelSelector(&spec.WorkloadSelector.LabelSelector, selectorPath.Child("labelSelector"))...)
+		allErrs = append(allErrs, validateLabelSelector(spec.WorkloadSelector.NamespaceSelector, selectorPath.Child("namespaceSelector"))...)
+	} else {
+		allErrs = append(allErrs, validateResourceRef(v.Mapper, spec.ResourceRef, specPath.Child("resourceRef"))...)
+	}
+
+	allErrs = append(allErrs, validateSchedule(spec.Schedule, specPath.Child("schedule"))...)
+	allErrs = append(allErrs, validateContainerRules(spec.ContainerRules, specPath.Child("containerRules"))...)
+
+	var oldClusters []string
+	var oldRegistry *migrationv1.Registry
+	var oldTargetCluster string
+	if old != nil {
+		oldClusters = old.Spec.SourceClusters
+		oldRegistry = &old.Spec.Registry
+		oldTargetCluster = old.Spec.TargetCluster
+	}
+
+	// Without source clusters the workloads are backed up wherever Karmada scheduled them
+	errs, err := validateClusters(ctx, v.Reader, spec.SourceClusters, oldClusters, specPath.Child("sourceClusters"))
+	if err != nil {
+		return err
+	}
+	allErrs = append(allErrs, errs...)
+
+	if spec.TargetCluster != "" && spec.TargetCluster != oldTargetCluster {
+		errs, err := validateCluster(ctx, v.Reader, spec.TargetCluster, specPath.Child("targetCluster"))
+		if err != nil {
+			return err
+		}
+		allErrs = append(allErrs, errs...)
+	}
+
+	errs, err = validateRegistry(ctx, v.Reader, statefulmigration.Namespace, spec.Registry, oldRegistry, specPath.Child("registry"))
+	if err != nil {
+		return err
+	}
+	allErrs = append(allErrs, errs...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(migrationv1.GroupVersion.WithKind("StatefulMigration").GroupKind(), statefulmigration.Name, allErrs)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+var _ = Describe("StatefulMigration Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *migrationv1.StatefulMigration
+		oldObj    *migrationv1.StatefulMigration
+		validator StatefulMigrationCustomValidator
+		defaulter StatefulMigrationCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &migrationv1.StatefulMigration{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: migrationv1.StatefulMigrationSpec{
+				ResourceRef: migrationv1.ResourceRef{
+					APIVersion: "apps/v1",
+					Kind:       "StatefulSet",
+					Namespace:  "default",
+					Name:       "web",
+				},
+				SourceClusters: []string{"member1"},
+				Registry: migrationv1.Registry{
+					URL:        "registry.example.com:5000",
+					Repository: "checkpoints",
+					SecretRef:  &migrationv1.SecretRef{Name: "registry-credentials"},
+				},
+				Schedule: "*/5 * * * *",
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = StatefulMigrationCustomValidator{
+			Reader: newFakeReader(
+				&clusterv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "member1"}},
+				&clusterv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "member2"}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "registry-credentials", Namespace: "default"}},
+			),
+			Mapper: mapper,
+		}
+		defaulter = StatefulMigrationCustomDefaulter{Mapper: mapper}
+	})
+
+	Context("When creating StatefulMigration under Defaulting Webhook", func() {
+		It("Should fill in the namespace and apiVersion of the resource reference", func() {
+			obj.Spec.ResourceRef = migrationv1.ResourceRef{Kind: "StatefulSet", Name: "web"}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.ResourceRef.Namespace).To(Equal("default"))
+			Expect(obj.Spec.ResourceRef.APIVersion).To(Equal("apps/v1"))
+
+			obj.Spec.ResourceRef = migrationv1.ResourceRef{Kind: "Pod", Name: "worker", Namespace: "jobs"}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.ResourceRef.Namespace).To(Equal("jobs"))
+			Expect(obj.Spec.ResourceRef.APIVersion).To(Equal("v1"))
+		})
+
+		It("Should leave unknown kinds for the validating webhook", func() {
+			obj.Spec.ResourceRef = migrationv1.ResourceRef{Kind: "CronTab", Name: "web"}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.ResourceRef.APIVersion).To(BeEmpty())
+		})
+	})
+
+	Context("When creating or updating StatefulMigration under Validating Webhook", func() {
+		It("Should admit a valid StatefulMigration", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny invalid schedules, kinds and registry URLs", func() {
+			obj.Spec.Schedule = "every five minutes"
+			obj.Spec.ResourceRef.Kind = "CronTab"
+			obj.Spec.Registry.URL = "ftp://registry.example.com"
+			obj.Spec.ContainerRules = []migrationv1.ContainerRule{{Names: []string{"istio-["}}}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(And(
+				ContainSubstring("spec.schedule"),
+				ContainSubstring("spec.resourceRef.kind"),
+				ContainSubstring("spec.registry.url"),
+				ContainSubstring("spec.containerRules[0].names[0]"),
+			))
+		})
+
+		It("Should deny missing source clusters and Secrets", func() {
+			// The source clusters are optional, they are derived from the ResourceBindings of the workloads
+			obj.Spec.SourceClusters = nil
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.SourceClusters = []string{"member1", "member3"}
+			obj.Spec.TargetCluster = "member4"
+			obj.Spec.Registry.SecretRef.Name = "missing"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(And(
+				ContainSubstring(`spec.sourceClusters[1]: Not found: "member3"`),
+				ContainSubstring(`spec.targetCluster: Not found: "member4"`),
+				ContainSubstring(`spec.registry.secretRef.name: Not found: "missing"`),
+			)))
+		})
+
+		It("Should only look up the clusters and Secret that changed on update", func() {
+			oldObj.Spec.SourceClusters = []string{"member1", "gone"}
+			oldObj.Spec.Registry.SecretRef.Name = "deleted"
+			obj = oldObj.DeepCopy()
+			obj.Spec.Schedule = "@hourly"
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
+
+			obj.Spec.SourceClusters = append(obj.Spec.SourceClusters, "member2", "member3")
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring(`spec.sourceClusters[3]: Not found: "member3"`)))
+			Expect(err).NotTo(MatchError(ContainSubstring("gone")))
+		})
+
+		It("Should validate the workload selector instead of the resource reference", func() {
+			obj.Spec.ResourceRef = migrationv1.ResourceRef{}
+			obj.Spec.WorkloadSelector = &migrationv1.WorkloadSelector{
+				LabelSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "backup", Operator: metav1.LabelSelectorOpIn},
+				}},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.workloadSelector.labelSelector")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.resourceRef")))
+		})
+	})
+})
@@ -0,0 +1,209 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	migrationv1 "github.com/lehuannhatrang/stateful-migration-operator/api/v1"
+)
+
+// defaultResourceRef fills the namespace of the resource reference from the namespace of the object
+// referencing it, and its apiVersion from its kind if the kind is served by a single group
+func defaultResourceRef(mapper meta.RESTMapper, resourceRef *migrationv1.ResourceRef, namespace string) {
+	if resourceRef.Namespace == "" {
+		resourceRef.Namespace = namespace
+	}
+	if resourceRef.APIVersion != "" || resourceRef.Kind == "" {
+		return
+	}
+
+	// An unknown or ambiguous kind is left for the validating webhook to reject
+	gvk, err := mapper.KindFor(schema.GroupVersionResource{Resource: strings.ToLower(resourceRef.Kind)})
+	if err != nil {
+		return
+	}
+	resourceRef.APIVersion = gvk.GroupVersion().String()
+}
+
+// validateSchedule checks that the schedule parses the way the agent parses it
+func validateSchedule(schedule string, fldPath *field.Path) field.ErrorList {
+	if _, err := cron.ParseStandard(schedule); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, schedule, err.Error())}
+	}
+	return nil
+}
+
+// validateResourceRef checks that the kind of the resource reference is served by the API server
+func validateResourceRef(mapper meta.RESTMapper, resourceRef migrationv1.ResourceRef, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if resourceRef.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "the name of the workload is required"))
+	}
+	if resourceRef.Kind == "" {
+		return append(allErrs, field.Required(fldPath.Child("kind"), "the kind of the workload is required"))
+	}
+
+	gv, err := schema.ParseGroupVersion(resourceRef.APIVersion)
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath.Child("apiVersion"), resourceRef.APIVersion, err.Error()))
+	}
+	kindPath := fldPath.Child("kind")
+	gvk, err := mapper.KindFor(gv.WithResource(strings.ToLower(resourceRef.Kind)))
+	if err != nil {
+		return append(allErrs, field.Invalid(kindPath, resourceRef.Kind, fmt.Sprintf("unsupported resource kind: %v", err)))
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return append(allErrs, field.Invalid(kindPath, resourceRef.Kind, fmt.Sprintf("unsupported resource kind: %v", err)))
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		allErrs = append(allErrs, field.Invalid(kindPath, resourceRef.Kind, "the workload must be namespaced"))
+	}
+	return allErrs
+}
+
+// validateRegistry checks that the registry URL is a [scheme://]host[:port][/path] and that its Secret exists.
+// The Secret is only looked up when it is new or changed, so that an object whose Secret was deleted can
+// still be updated or have its Secret replaced.
+func validateRegistry(ctx context.Context, reader client.Reader, namespace string, registry migrationv1.Registry, oldRegistry *migrationv1.Registry, fldPath *field.Path) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+
+	raw := registry.URL
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	registryURL, err := url.Parse(raw)
+	switch {
+	case err != nil:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), registry.URL, err.Error()))
+	case registryURL.Scheme != "http" && registryURL.Scheme != "https":
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), registry.URL, "the scheme must be http or https"))
+	case registryURL.Hostname() == "" || registryURL.User != nil || registryURL.RawQuery != "" || registryURL.Fragment != "":
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), registry.URL, "expected [scheme://]host[:port][/path]"))
+	}
+
+	if strings.Trim(registry.Repository, "/") == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("repository"), "the repository is required"))
+	}
+
+	if registry.SecretRef == nil {
+		return allErrs, nil
+	}
+	secretPath := fldPath.Child("secretRef", "name")
+	if oldRegistry != nil && oldRegistry.SecretRef != nil && oldRegistry.SecretRef.Name == registry.SecretRef.Name {
+		return allErrs, nil
+	}
+	var secret corev1.Secret
+	if err := reader.Get(ctx, types.NamespacedName{Name: registry.SecretRef.Name, Namespace: namespace}, &secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		allErrs = append(allErrs, field.NotFound(secretPath, registry.SecretRef.Name))
+	}
+	return allErrs, nil
+}
+
+// validateClusters checks that the clusters are Karmada member clusters. Clusters that were already
+// listed are not looked up again, so that a cluster that left Karmada can still be removed.
+func validateClusters(ctx context.Context, reader client.Reader, clusters, oldClusters []string, fldPath *field.Path) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+	for i, name := range clusters {
+		if slices.Contains(clusters[:i], name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), name))
+			continue
+		}
+		if slices.Contains(oldClusters, name) {
+			continue
+		}
+		errs, err := validateCluster(ctx, reader, name, fldPath.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		allErrs = append(allErrs, errs...)
+	}
+	return allErrs, nil
+}
+
+// validateCluster checks that the cluster is a Karmada member cluster
+func validateCluster(ctx context.Context, reader client.Reader, name string, fldPath *field.Path) (field.ErrorList, error) {
+	var cluster clusterv1alpha1.Cluster
+	if err := reader.Get(ctx, types.NamespacedName{Name: name}, &cluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		return field.ErrorList{field.NotFound(fldPath, name)}, nil
+	}
+	return nil, nil
+}
+
+// validateContainerRules checks that the name patterns of the container rules are well-formed
+func validateContainerRules(rules []migrationv1.ContainerRule, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, rule := range rules {
+		for j, pattern := range rule.Names {
+			if _, err := path.Match(pattern, ""); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("names").Index(j), pattern, err.Error()))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateLabelSelector checks that the label selector can be converted into a selector
+func validateLabelSelector(selector *metav1.LabelSelector, fldPath *field.Path) field.ErrorList {
+	if selector == nil {
+		return nil
+	}
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, selector, err.Error())}
+	}
+	return nil
+}
+
+// validateContainers checks that the containers have unique, valid names
+func validateContainers(containers []migrationv1.Container, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := make(map[string]bool, len(containers))
+	for i, container := range containers {
+		if names[container.Name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), container.Name))
+		}
+		names[container.Name] = true
+		for _, msg := range validation.IsDNS1123Label(container.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("name"), container.Name, msg))
+		}
+	}
+	return allErrs
+}